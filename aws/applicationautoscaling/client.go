@@ -0,0 +1,206 @@
+package applicationautoscaling
+
+import (
+	"errors"
+	"fmt"
+
+	_aws "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	_aas "github.com/aws/aws-sdk-go/service/applicationautoscaling"
+)
+
+type Client struct {
+	svc       *_aas.ApplicationAutoScaling
+	awsRegion string
+}
+
+func New(session *session.Session, config *_aws.Config) *Client {
+	return &Client{
+		awsRegion: *config.Region,
+		svc:       _aas.New(session, config),
+	}
+}
+
+// RegisterECSServiceScalableTarget registers (or updates) the desired count of the ECS Service as a scalable target.
+// Application Auto Scaling uses its service-linked role for ECS.
+func (c *Client) RegisterECSServiceScalableTarget(clusterName, serviceName string, minCapacity, maxCapacity uint16) error {
+	if clusterName == "" {
+		return errors.New("clusterName is empty")
+	}
+	if serviceName == "" {
+		return errors.New("serviceName is empty")
+	}
+
+	params := &_aas.RegisterScalableTargetInput{
+		ServiceNamespace:  _aws.String(_aas.ServiceNamespaceEcs),
+		ScalableDimension: _aws.String(_aas.ScalableDimensionEcsServiceDesiredCount),
+		ResourceId:        _aws.String(ecsServiceResourceID(clusterName, serviceName)),
+		MinCapacity:       _aws.Int64(int64(minCapacity)),
+		MaxCapacity:       _aws.Int64(int64(maxCapacity)),
+	}
+
+	_, err := c.svc.RegisterScalableTarget(params)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RetrieveECSServiceScalableTarget returns nil if the ECS Service is not registered as a scalable target.
+func (c *Client) RetrieveECSServiceScalableTarget(clusterName, serviceName string) (*_aas.ScalableTarget, error) {
+	if clusterName == "" {
+		return nil, errors.New("clusterName is empty")
+	}
+	if serviceName == "" {
+		return nil, errors.New("serviceName is empty")
+	}
+
+	params := &_aas.DescribeScalableTargetsInput{
+		ServiceNamespace:  _aws.String(_aas.ServiceNamespaceEcs),
+		ScalableDimension: _aws.String(_aas.ScalableDimensionEcsServiceDesiredCount),
+		ResourceIds:       _aws.StringSlice([]string{ecsServiceResourceID(clusterName, serviceName)}),
+	}
+
+	res, err := c.svc.DescribeScalableTargets(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.ScalableTargets) == 0 {
+		return nil, nil
+	} else if len(res.ScalableTargets) == 1 {
+		return res.ScalableTargets[0], nil
+	}
+
+	return nil, fmt.Errorf("Invalid result: %v", res.ScalableTargets)
+}
+
+// DeregisterECSServiceScalableTarget deregisters the ECS Service. Its scaling policies are deleted as well.
+func (c *Client) DeregisterECSServiceScalableTarget(clusterName, serviceName string) error {
+	if clusterName == "" {
+		return errors.New("clusterName is empty")
+	}
+	if serviceName == "" {
+		return errors.New("serviceName is empty")
+	}
+
+	params := &_aas.DeregisterScalableTargetInput{
+		ServiceNamespace:  _aws.String(_aas.ServiceNamespaceEcs),
+		ScalableDimension: _aws.String(_aas.ScalableDimensionEcsServiceDesiredCount),
+		ResourceId:        _aws.String(ecsServiceResourceID(clusterName, serviceName)),
+	}
+
+	_, err := c.svc.DeregisterScalableTarget(params)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PutECSServiceTargetTrackingPolicy creates or updates a target tracking scaling policy of the ECS Service.
+// resourceLabel is required only for ALBRequestCountPerTarget metric.
+func (c *Client) PutECSServiceTargetTrackingPolicy(clusterName, serviceName, policyName, metricType, resourceLabel string, targetValue float64) error {
+	if clusterName == "" {
+		return errors.New("clusterName is empty")
+	}
+	if serviceName == "" {
+		return errors.New("serviceName is empty")
+	}
+	if policyName == "" {
+		return errors.New("policyName is empty")
+	}
+
+	metric := &_aas.PredefinedMetricSpecification{
+		PredefinedMetricType: _aws.String(metricType),
+	}
+	if resourceLabel != "" {
+		metric.ResourceLabel = _aws.String(resourceLabel)
+	}
+
+	params := &_aas.PutScalingPolicyInput{
+		ServiceNamespace:  _aws.String(_aas.ServiceNamespaceEcs),
+		ScalableDimension: _aws.String(_aas.ScalableDimensionEcsServiceDesiredCount),
+		ResourceId:        _aws.String(ecsServiceResourceID(clusterName, serviceName)),
+		PolicyName:        _aws.String(policyName),
+		PolicyType:        _aws.String(_aas.PolicyTypeTargetTrackingScaling),
+		TargetTrackingScalingPolicyConfiguration: &_aas.TargetTrackingScalingPolicyConfiguration{
+			PredefinedMetricSpecification: metric,
+			TargetValue:                   _aws.Float64(targetValue),
+		},
+	}
+
+	_, err := c.svc.PutScalingPolicy(params)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) RetrieveECSServiceScalingPolicies(clusterName, serviceName string) ([]*_aas.ScalingPolicy, error) {
+	if clusterName == "" {
+		return nil, errors.New("clusterName is empty")
+	}
+	if serviceName == "" {
+		return nil, errors.New("serviceName is empty")
+	}
+
+	var nextToken *string
+	policies := []*_aas.ScalingPolicy{}
+
+	for {
+		params := &_aas.DescribeScalingPoliciesInput{
+			ServiceNamespace:  _aws.String(_aas.ServiceNamespaceEcs),
+			ScalableDimension: _aws.String(_aas.ScalableDimensionEcsServiceDesiredCount),
+			ResourceId:        _aws.String(ecsServiceResourceID(clusterName, serviceName)),
+			NextToken:         nextToken,
+		}
+
+		res, err := c.svc.DescribeScalingPolicies(params)
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, res.ScalingPolicies...)
+
+		if res.NextToken == nil {
+			break
+		} else {
+			nextToken = res.NextToken
+		}
+	}
+
+	return policies, nil
+}
+
+func (c *Client) DeleteECSServiceScalingPolicy(clusterName, serviceName, policyName string) error {
+	if clusterName == "" {
+		return errors.New("clusterName is empty")
+	}
+	if serviceName == "" {
+		return errors.New("serviceName is empty")
+	}
+	if policyName == "" {
+		return errors.New("policyName is empty")
+	}
+
+	params := &_aas.DeleteScalingPolicyInput{
+		ServiceNamespace:  _aws.String(_aas.ServiceNamespaceEcs),
+		ScalableDimension: _aws.String(_aas.ScalableDimensionEcsServiceDesiredCount),
+		ResourceId:        _aws.String(ecsServiceResourceID(clusterName, serviceName)),
+		PolicyName:        _aws.String(policyName),
+	}
+
+	_, err := c.svc.DeleteScalingPolicy(params)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ecsServiceResourceID(clusterName, serviceName string) string {
+	return fmt.Sprintf("service/%s/%s", clusterName, serviceName)
+}
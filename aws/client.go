@@ -4,11 +4,13 @@ import (
 	_aws "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/coldbrewcloud/coldbrew-cli/aws/applicationautoscaling"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/autoscaling"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/ec2"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/ecr"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/ecs"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/elb"
+	"github.com/coldbrewcloud/coldbrew-cli/aws/events"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/iam"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/logs"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/sns"
@@ -18,14 +20,16 @@ type Client struct {
 	session *session.Session
 	config  *_aws.Config
 
-	autoScalingClient *autoscaling.Client
-	ec2Client         *ec2.Client
-	ecsClient         *ecs.Client
-	elbClient         *elb.Client
-	ecrClient         *ecr.Client
-	iamClient         *iam.Client
-	snsClient         *sns.Client
-	logsClient        *logs.Client
+	autoScalingClient            *autoscaling.Client
+	applicationAutoScalingClient *applicationautoscaling.Client
+	ec2Client                    *ec2.Client
+	ecsClient                    *ecs.Client
+	elbClient                    *elb.Client
+	ecrClient                    *ecr.Client
+	iamClient                    *iam.Client
+	snsClient                    *sns.Client
+	logsClient                   *logs.Client
+	eventsClient                 *events.Client
 }
 
 func NewClient(region, accessKey, secretKey string) *Client {
@@ -47,6 +51,13 @@ func (c *Client) AutoScaling() *autoscaling.Client {
 	return c.autoScalingClient
 }
 
+func (c *Client) ApplicationAutoScaling() *applicationautoscaling.Client {
+	if c.applicationAutoScalingClient == nil {
+		c.applicationAutoScalingClient = applicationautoscaling.New(c.session, c.config)
+	}
+	return c.applicationAutoScalingClient
+}
+
 func (c *Client) EC2() *ec2.Client {
 	if c.ec2Client == nil {
 		c.ec2Client = ec2.New(c.session, c.config)
@@ -95,3 +106,10 @@ func (c *Client) CloudWatchLogs() *logs.Client {
 	}
 	return c.logsClient
 }
+
+func (c *Client) CloudWatchEvents() *events.Client {
+	if c.eventsClient == nil {
+		c.eventsClient = events.New(c.session, c.config)
+	}
+	return c.eventsClient
+}
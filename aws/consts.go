@@ -19,4 +19,23 @@ const (
 	ECSTaskDefinitionLogDriverGelf     = "gelf"
 	ECSTaskDefinitionLogDriverFluentd  = "fluentd"
 	ECSTaskDefinitionLogDriverSplunk   = "splunk"
+
+	ECSContainerDependencyConditionStart    = "START"
+	ECSContainerDependencyConditionComplete = "COMPLETE"
+	ECSContainerDependencyConditionSuccess  = "SUCCESS"
+	ECSContainerDependencyConditionHealthy  = "HEALTHY"
+
+	ECSPlacementStrategyTypeSpread  = "spread"
+	ECSPlacementStrategyTypeBinpack = "binpack"
+	ECSPlacementStrategyTypeRandom  = "random"
+
+	ECSPlacementConstraintTypeDistinctInstance = "distinctInstance"
+	ECSPlacementConstraintTypeMemberOf         = "memberOf"
+
+	ECSVolumeTypeHost   = "host"
+	ECSVolumeTypeDocker = "docker"
+	ECSVolumeTypeEFS    = "efs"
+
+	ECSDockerVolumeScopeTask   = "task"
+	ECSDockerVolumeScopeShared = "shared"
 )
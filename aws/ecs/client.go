@@ -3,6 +3,8 @@ package ecs
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	_aws "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -10,6 +12,8 @@ import (
 	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
 )
 
+const maxDescribeTasks = 100
+
 type Client struct {
 	svc       *_ecs.ECS
 	awsRegion string
@@ -71,51 +75,117 @@ func (c *Client) DeleteCluster(clusterName string) error {
 	return err
 }
 
-func (c *Client) UpdateTaskDefinition(taskDefinitionName, image, taskContainerName string, cpu, memory uint64, envs map[string]string, portMappings []PortMapping, logDriver string, logDriverOptions map[string]string) (*_ecs.TaskDefinition, error) {
-	if taskDefinitionName == "" {
-		return nil, errors.New("taskDefinitionName is empty")
+func (c *Client) UpdateTaskDefinition(input *TaskDefinitionInput) (*_ecs.TaskDefinition, error) {
+	if input == nil {
+		return nil, errors.New("input is nil")
 	}
-	if image == "" {
-		return nil, errors.New("image is empty")
+	if input.Family == "" {
+		return nil, errors.New("family is empty")
 	}
-	if taskContainerName == "" {
-		return nil, errors.New("taskContainerName is empty")
+	if len(input.Containers) == 0 {
+		return nil, errors.New("containers is empty")
 	}
 
 	params := &_ecs.RegisterTaskDefinitionInput{
-		ContainerDefinitions: []*_ecs.ContainerDefinition{
-			{
-				Name:             _aws.String(taskContainerName),
-				Cpu:              _aws.Int64(int64(cpu)),
-				Memory:           _aws.Int64(int64(memory)),
-				Essential:        _aws.Bool(true),
-				Image:            _aws.String(image),
-				LogConfiguration: nil,
-			},
-		},
-		Family: _aws.String(taskDefinitionName),
+		Family: _aws.String(input.Family),
+	}
+	if input.ExecutionRoleARN != "" {
+		params.ExecutionRoleArn = _aws.String(input.ExecutionRoleARN)
+	}
+	if input.TaskRoleARN != "" {
+		params.TaskRoleArn = _aws.String(input.TaskRoleARN)
 	}
 
-	if logDriver != "" {
-		params.ContainerDefinitions[0].LogConfiguration = &_ecs.LogConfiguration{
-			LogDriver: _aws.String(logDriver),
-			Options:   _aws.StringMap(logDriverOptions),
+	for _, container := range input.Containers {
+		if container.Name == "" {
+			return nil, errors.New("container name is empty")
+		}
+		if container.Image == "" {
+			return nil, fmt.Errorf("image is empty for container [%s]", container.Name)
 		}
-	}
 
-	for ek, ev := range envs {
-		params.ContainerDefinitions[0].Environment = append(params.ContainerDefinitions[0].Environment, &_ecs.KeyValuePair{
-			Name:  _aws.String(ek),
-			Value: _aws.String(ev),
-		})
+		containerDefinition := &_ecs.ContainerDefinition{
+			Name:             _aws.String(container.Name),
+			Cpu:              _aws.Int64(int64(container.CPU)),
+			Memory:           _aws.Int64(int64(container.Memory)),
+			Essential:        _aws.Bool(container.Essential),
+			Image:            _aws.String(container.Image),
+			LogConfiguration: nil,
+		}
+
+		// ECS requires the hard limit to be greater than the soft limit: the same value is just the hard limit
+		if container.MemoryReservation > 0 && container.MemoryReservation < container.Memory {
+			containerDefinition.MemoryReservation = _aws.Int64(int64(container.MemoryReservation))
+		}
+
+		if container.LogDriver != "" {
+			containerDefinition.LogConfiguration = &_ecs.LogConfiguration{
+				LogDriver: _aws.String(container.LogDriver),
+				Options:   _aws.StringMap(container.LogDriverOptions),
+			}
+		}
+
+		for ek, ev := range container.Envs {
+			containerDefinition.Environment = append(containerDefinition.Environment, &_ecs.KeyValuePair{
+				Name:  _aws.String(ek),
+				Value: _aws.String(ev),
+			})
+		}
+
+		for sk, sv := range container.Secrets {
+			containerDefinition.Secrets = append(containerDefinition.Secrets, &_ecs.Secret{
+				Name:      _aws.String(sk),
+				ValueFrom: _aws.String(sv),
+			})
+		}
+
+		for _, pm := range container.PortMappings {
+			containerDefinition.PortMappings = append(containerDefinition.PortMappings, &_ecs.PortMapping{
+				ContainerPort: _aws.Int64(int64(pm.ContainerPort)),
+				HostPort:      _aws.Int64(int64(pm.HostPort)),
+				Protocol:      _aws.String(pm.Protocol),
+			})
+		}
+
+		for _, d := range container.DependsOn {
+			containerDefinition.DependsOn = append(containerDefinition.DependsOn, &_ecs.ContainerDependency{
+				ContainerName: _aws.String(d.ContainerName),
+				Condition:     _aws.String(d.Condition),
+			})
+		}
+
+		if len(container.Links) > 0 {
+			containerDefinition.Links = _aws.StringSlice(container.Links)
+		}
+
+		for _, mp := range container.MountPoints {
+			containerDefinition.MountPoints = append(containerDefinition.MountPoints, &_ecs.MountPoint{
+				SourceVolume:  _aws.String(mp.SourceVolume),
+				ContainerPath: _aws.String(mp.ContainerPath),
+				ReadOnly:      _aws.Bool(mp.ReadOnly),
+			})
+		}
+
+		if container.HealthCheck != nil {
+			containerDefinition.HealthCheck = &_ecs.HealthCheck{
+				Command:     _aws.StringSlice(container.HealthCheck.Command),
+				Interval:    _aws.Int64(int64(container.HealthCheck.Interval)),
+				Timeout:     _aws.Int64(int64(container.HealthCheck.Timeout)),
+				Retries:     _aws.Int64(int64(container.HealthCheck.Retries)),
+				StartPeriod: _aws.Int64(int64(container.HealthCheck.StartPeriod)),
+			}
+		}
+
+		setRuntimeOptions(containerDefinition, container)
+
+		params.ContainerDefinitions = append(params.ContainerDefinitions, containerDefinition)
 	}
 
-	for _, pm := range portMappings {
-		params.ContainerDefinitions[0].PortMappings = append(params.ContainerDefinitions[0].PortMappings, &_ecs.PortMapping{
-			ContainerPort: _aws.Int64(int64(pm.ContainerPort)),
-			HostPort:      _aws.Int64(0),
-			Protocol:      _aws.String(pm.Protocol),
-		})
+	for _, volume := range input.Volumes {
+		if volume.Name == "" {
+			return nil, errors.New("volume name is empty")
+		}
+		params.Volumes = append(params.Volumes, ecsVolume(volume))
 	}
 
 	res, err := c.svc.RegisterTaskDefinition(params)
@@ -126,6 +196,97 @@ func (c *Client) UpdateTaskDefinition(taskDefinitionName, image, taskContainerNa
 	return res.TaskDefinition, nil
 }
 
+func setRuntimeOptions(containerDefinition *_ecs.ContainerDefinition, container *ContainerDefinition) {
+	if len(container.Command) > 0 {
+		containerDefinition.Command = _aws.StringSlice(container.Command)
+	}
+	if len(container.EntryPoint) > 0 {
+		containerDefinition.EntryPoint = _aws.StringSlice(container.EntryPoint)
+	}
+	if container.WorkingDirectory != "" {
+		containerDefinition.WorkingDirectory = _aws.String(container.WorkingDirectory)
+	}
+	if container.User != "" {
+		containerDefinition.User = _aws.String(container.User)
+	}
+	for _, u := range container.Ulimits {
+		containerDefinition.Ulimits = append(containerDefinition.Ulimits, &_ecs.Ulimit{
+			Name:      _aws.String(u.Name),
+			SoftLimit: _aws.Int64(int64(u.SoftLimit)),
+			HardLimit: _aws.Int64(int64(u.HardLimit)),
+		})
+	}
+	if len(container.DockerLabels) > 0 {
+		containerDefinition.DockerLabels = _aws.StringMap(container.DockerLabels)
+	}
+	hostnames := []string{}
+	for hostname := range container.ExtraHosts {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+	for _, hostname := range hostnames {
+		containerDefinition.ExtraHosts = append(containerDefinition.ExtraHosts, &_ecs.HostEntry{
+			Hostname:  _aws.String(hostname),
+			IpAddress: _aws.String(container.ExtraHosts[hostname]),
+		})
+	}
+	if len(container.DNSServers) > 0 {
+		containerDefinition.DnsServers = _aws.StringSlice(container.DNSServers)
+	}
+	if container.StopTimeout > 0 {
+		containerDefinition.StopTimeout = _aws.Int64(int64(container.StopTimeout))
+	}
+}
+
+func ecsVolume(volume *Volume) *_ecs.Volume {
+	v := &_ecs.Volume{Name: _aws.String(volume.Name)}
+
+	switch {
+	case volume.Docker != nil:
+		v.DockerVolumeConfiguration = &_ecs.DockerVolumeConfiguration{
+			Scope: _aws.String(volume.Docker.Scope),
+		}
+		if volume.Docker.Driver != "" {
+			v.DockerVolumeConfiguration.Driver = _aws.String(volume.Docker.Driver)
+		}
+		if len(volume.Docker.DriverOptions) > 0 {
+			v.DockerVolumeConfiguration.DriverOpts = _aws.StringMap(volume.Docker.DriverOptions)
+		}
+		if volume.Docker.Scope == _ecs.ScopeShared {
+			v.DockerVolumeConfiguration.Autoprovision = _aws.Bool(volume.Docker.Autoprovision)
+		}
+	case volume.EFS != nil:
+		v.EfsVolumeConfiguration = &_ecs.EFSVolumeConfiguration{
+			FileSystemId:      _aws.String(volume.EFS.FileSystemID),
+			TransitEncryption: _aws.String(_ecs.EFSTransitEncryptionDisabled),
+		}
+		if volume.EFS.RootDirectory != "" {
+			v.EfsVolumeConfiguration.RootDirectory = _aws.String(volume.EFS.RootDirectory)
+		}
+		if volume.EFS.TransitEncryption {
+			v.EfsVolumeConfiguration.TransitEncryption = _aws.String(_ecs.EFSTransitEncryptionEnabled)
+		}
+		if volume.EFS.AccessPointID != "" || volume.EFS.IAM {
+			v.EfsVolumeConfiguration.AuthorizationConfig = &_ecs.EFSAuthorizationConfig{
+				Iam: _aws.String(_ecs.EFSAuthorizationConfigIAMDisabled),
+			}
+			if volume.EFS.AccessPointID != "" {
+				v.EfsVolumeConfiguration.AuthorizationConfig.AccessPointId = _aws.String(volume.EFS.AccessPointID)
+			}
+			if volume.EFS.IAM {
+				v.EfsVolumeConfiguration.AuthorizationConfig.Iam = _aws.String(_ecs.EFSAuthorizationConfigIAMEnabled)
+			}
+		}
+	default:
+		v.Host = &_ecs.HostVolumeProperties{}
+		if volume.HostPath != "" {
+			v.Host.SourcePath = _aws.String(volume.HostPath)
+		}
+	}
+
+	return v
+}
+
 func (c *Client) RetrieveTaskDefinition(taskDefinitionNameOrARN string) (*_ecs.TaskDefinition, error) {
 	params := &_ecs.DescribeTaskDefinitionInput{
 		TaskDefinition: _aws.String(taskDefinitionNameOrARN),
@@ -139,6 +300,48 @@ func (c *Client) RetrieveTaskDefinition(taskDefinitionNameOrARN string) (*_ecs.T
 	return res.TaskDefinition, nil
 }
 
+// ListTaskDefinitionARNs returns ARNs of all active revisions of the task definition family
+// in ascending revision order.
+func (c *Client) ListTaskDefinitionARNs(familyName string) ([]string, error) {
+	if familyName == "" {
+		return nil, errors.New("familyName is empty")
+	}
+
+	// family prefix also matches other families (e.g. "echo" matches "echo2")
+	familyToken := fmt.Sprintf("task-definition/%s:", familyName)
+
+	var nextToken *string
+	taskDefinitionARNs := []string{}
+
+	for {
+		params := &_ecs.ListTaskDefinitionsInput{
+			FamilyPrefix: _aws.String(familyName),
+			Status:       _aws.String("ACTIVE"),
+			Sort:         _aws.String("ASC"),
+			NextToken:    nextToken,
+		}
+
+		res, err := c.svc.ListTaskDefinitions(params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range res.TaskDefinitionArns {
+			if strings.Contains(conv.S(t), familyToken) {
+				taskDefinitionARNs = append(taskDefinitionARNs, conv.S(t))
+			}
+		}
+
+		if res.NextToken == nil {
+			break
+		} else {
+			nextToken = res.NextToken
+		}
+	}
+
+	return taskDefinitionARNs, nil
+}
+
 func (c *Client) RetrieveService(clusterName, serviceName string) (*_ecs.Service, error) {
 	if clusterName == "" {
 		return nil, errors.New("clusterName is empty")
@@ -166,7 +369,9 @@ func (c *Client) RetrieveService(clusterName, serviceName string) (*_ecs.Service
 	return nil, fmt.Errorf("Invalid result: %v", res.Services)
 }
 
-func (c *Client) CreateService(clusterName, serviceName, taskDefARN string, desiredCount uint16, loadBalancers []*LoadBalancer, serviceRole string) (*_ecs.Service, error) {
+// CreateService creates a new ECS Service. If deployment is nil, ECS defaults (200% max, 50% min healthy) are used.
+// If placement is nil, ECS places tasks without any strategies or constraints.
+func (c *Client) CreateService(clusterName, serviceName, taskDefARN string, desiredCount uint16, loadBalancers []*LoadBalancer, serviceRole string, deployment *DeploymentConfiguration, placement *Placement) (*_ecs.Service, error) {
 	if clusterName == "" {
 		return nil, errors.New("clusterName is empty")
 	}
@@ -187,6 +392,14 @@ func (c *Client) CreateService(clusterName, serviceName, taskDefARN string, desi
 			MinimumHealthyPercent: _aws.Int64(50),
 		},
 	}
+	if deployment != nil {
+		params.DeploymentConfiguration = deploymentConfiguration(deployment)
+	}
+
+	if placement != nil {
+		params.PlacementStrategy = placementStrategies(placement)
+		params.PlacementConstraints = placementConstraints(placement)
+	}
 
 	if loadBalancers != nil && len(loadBalancers) > 0 {
 		params.LoadBalancers = []*_ecs.LoadBalancer{}
@@ -211,7 +424,10 @@ func (c *Client) CreateService(clusterName, serviceName, taskDefARN string, desi
 	return res.Service, nil
 }
 
-func (c *Client) UpdateService(clusterName, serviceName, taskDefARN string, desiredCount uint16) (*_ecs.Service, error) {
+// UpdateService updates the ECS Task Definition and the desired count of the ECS Service. If desiredCount,
+// deployment, or placement is nil, the current desired count, deployment configuration, or task placement
+// of the service is kept.
+func (c *Client) UpdateService(clusterName, serviceName, taskDefARN string, desiredCount *uint16, deployment *DeploymentConfiguration, placement *Placement) (*_ecs.Service, error) {
 	if clusterName == "" {
 		return nil, errors.New("clusterName is empty")
 	}
@@ -225,12 +441,17 @@ func (c *Client) UpdateService(clusterName, serviceName, taskDefARN string, desi
 	params := &_ecs.UpdateServiceInput{
 		Service:        _aws.String(serviceName),
 		Cluster:        _aws.String(clusterName),
-		DesiredCount:   _aws.Int64(int64(desiredCount)),
 		TaskDefinition: _aws.String(taskDefARN),
-		DeploymentConfiguration: &_ecs.DeploymentConfiguration{
-			MaximumPercent:        _aws.Int64(200),
-			MinimumHealthyPercent: _aws.Int64(50),
-		},
+	}
+	if desiredCount != nil {
+		params.DesiredCount = _aws.Int64(int64(*desiredCount))
+	}
+	if deployment != nil {
+		params.DeploymentConfiguration = deploymentConfiguration(deployment)
+	}
+	if placement != nil {
+		params.PlacementStrategy = placementStrategies(placement)
+		params.PlacementConstraints = placementConstraints(placement)
 	}
 
 	res, err := c.svc.UpdateService(params)
@@ -241,6 +462,43 @@ func (c *Client) UpdateService(clusterName, serviceName, taskDefARN string, desi
 	return res.Service, nil
 }
 
+func deploymentConfiguration(deployment *DeploymentConfiguration) *_ecs.DeploymentConfiguration {
+	return &_ecs.DeploymentConfiguration{
+		MaximumPercent:        _aws.Int64(int64(deployment.MaxPercent)),
+		MinimumHealthyPercent: _aws.Int64(int64(deployment.MinHealthyPercent)),
+		DeploymentCircuitBreaker: &_ecs.DeploymentCircuitBreaker{
+			Enable:   _aws.Bool(deployment.CircuitBreaker),
+			Rollback: _aws.Bool(deployment.CircuitBreakerRollback),
+		},
+	}
+}
+
+// placementStrategies returns a non-nil list even if placement has no strategies, so that updating an ECS Service
+// removes its existing strategies.
+func placementStrategies(placement *Placement) []*_ecs.PlacementStrategy {
+	strategies := []*_ecs.PlacementStrategy{}
+	for _, s := range placement.Strategies {
+		strategy := &_ecs.PlacementStrategy{Type: _aws.String(s.Type)}
+		if s.Field != "" {
+			strategy.Field = _aws.String(s.Field)
+		}
+		strategies = append(strategies, strategy)
+	}
+	return strategies
+}
+
+func placementConstraints(placement *Placement) []*_ecs.PlacementConstraint {
+	constraints := []*_ecs.PlacementConstraint{}
+	for _, c := range placement.Constraints {
+		constraint := &_ecs.PlacementConstraint{Type: _aws.String(c.Type)}
+		if c.Expression != "" {
+			constraint.Expression = _aws.String(c.Expression)
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints
+}
+
 func (c *Client) DeleteService(clusterName, serviceName string) error {
 	params := &_ecs.DeleteServiceInput{
 		Cluster: _aws.String(clusterName),
@@ -253,14 +511,25 @@ func (c *Client) DeleteService(clusterName, serviceName string) error {
 }
 
 func (c *Client) ListServiceTaskARNs(clusterName, serviceName string) ([]string, error) {
+	return c.listServiceTaskARNs(clusterName, serviceName, _ecs.DesiredStatusRunning)
+}
+
+// ListServiceStoppedTaskARNs returns the ARNs of the stopped tasks of the ECS Service. ECS keeps stopped tasks
+// for a short time only (about an hour).
+func (c *Client) ListServiceStoppedTaskARNs(clusterName, serviceName string) ([]string, error) {
+	return c.listServiceTaskARNs(clusterName, serviceName, _ecs.DesiredStatusStopped)
+}
+
+func (c *Client) listServiceTaskARNs(clusterName, serviceName, desiredStatus string) ([]string, error) {
 	var nextToken *string
 	taskARNs := []string{}
 
 	for {
 		params := &_ecs.ListTasksInput{
-			Cluster:     _aws.String(clusterName),
-			ServiceName: _aws.String(serviceName),
-			NextToken:   nextToken,
+			Cluster:       _aws.String(clusterName),
+			ServiceName:   _aws.String(serviceName),
+			DesiredStatus: _aws.String(desiredStatus),
+			NextToken:     nextToken,
 		}
 
 		res, err := c.svc.ListTasks(params)
@@ -283,21 +552,85 @@ func (c *Client) ListServiceTaskARNs(clusterName, serviceName string) ([]string,
 }
 
 func (c *Client) RetrieveTasks(clusterName string, taskARNs []string) ([]*_ecs.Task, error) {
-	if len(taskARNs) == 0 {
-		return []*_ecs.Task{}, nil
+	tasks := []*_ecs.Task{}
+
+	// DescribeTasks accepts up to 100 tasks at a time
+	for len(taskARNs) > 0 {
+		batch := taskARNs
+		if len(batch) > maxDescribeTasks {
+			batch = batch[:maxDescribeTasks]
+		}
+		taskARNs = taskARNs[len(batch):]
+
+		params := &_ecs.DescribeTasksInput{
+			Cluster: _aws.String(clusterName),
+			Tasks:   _aws.StringSlice(batch),
+		}
+
+		res, err := c.svc.DescribeTasks(params)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, res.Tasks...)
 	}
 
-	params := &_ecs.DescribeTasksInput{
-		Cluster: _aws.String(clusterName),
-		Tasks:   _aws.StringSlice(taskARNs),
+	return tasks, nil
+}
+
+// RunTask starts a single task of the ECS Task Definition outside of any ECS Service. If containerName is
+// not empty, the command and envs override the container's command and environment variables.
+func (c *Client) RunTask(clusterName, taskDefARN, containerName string, command []string, envs map[string]string, startedBy string) (*_ecs.Task, error) {
+	if clusterName == "" {
+		return nil, errors.New("clusterName is empty")
+	}
+	if taskDefARN == "" {
+		return nil, errors.New("taskDefARN is empty")
+	}
+
+	params := &_ecs.RunTaskInput{
+		Cluster:        _aws.String(clusterName),
+		TaskDefinition: _aws.String(taskDefARN),
+		Count:          _aws.Int64(1),
+	}
+	if startedBy != "" {
+		params.StartedBy = _aws.String(startedBy)
+	}
+	if containerName != "" {
+		override := &_ecs.ContainerOverride{
+			Name: _aws.String(containerName),
+		}
+		if len(command) > 0 {
+			override.Command = _aws.StringSlice(command)
+		}
+		envNames := []string{}
+		for name := range envs {
+			envNames = append(envNames, name)
+		}
+		sort.Strings(envNames)
+		for _, name := range envNames {
+			override.Environment = append(override.Environment, &_ecs.KeyValuePair{
+				Name:  _aws.String(name),
+				Value: _aws.String(envs[name]),
+			})
+		}
+		params.Overrides = &_ecs.TaskOverride{
+			ContainerOverrides: []*_ecs.ContainerOverride{override},
+		}
 	}
 
-	res, err := c.svc.DescribeTasks(params)
+	res, err := c.svc.RunTask(params)
 	if err != nil {
 		return nil, err
 	}
 
-	return res.Tasks, nil
+	if len(res.Failures) > 0 {
+		return nil, fmt.Errorf("%s (%s)", conv.S(res.Failures[0].Reason), conv.S(res.Failures[0].Arn))
+	}
+	if len(res.Tasks) != 1 {
+		return nil, fmt.Errorf("Invalid result: %v", res.Tasks)
+	}
+
+	return res.Tasks[0], nil
 }
 
 func (c *Client) ListContainerInstanceARNs(clusterName string) ([]string, error) {
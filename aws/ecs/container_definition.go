@@ -0,0 +1,50 @@
+package ecs
+
+type ContainerDefinition struct {
+	Name              string                `json:"name"`
+	Image             string                `json:"image"`
+	CPU               uint64                `json:"cpu"`
+	Memory            uint64                `json:"memory"`             // hard limit in MB
+	MemoryReservation uint64                `json:"memory_reservation"` // soft limit in MB (0: same as hard limit)
+	Essential         bool                  `json:"essential"`
+	Envs              map[string]string     `json:"envs"`
+	Secrets           map[string]string     `json:"secrets"` // env name -> SSM parameter or Secrets Manager secret
+	PortMappings      []PortMapping         `json:"port_mappings"`
+	DependsOn         []ContainerDependency `json:"depends_on"`
+	Links             []string              `json:"links"`
+	MountPoints       []MountPoint          `json:"mount_points"`
+	HealthCheck       *HealthCheck          `json:"health_check"` // nil: no container health check
+	LogDriver         string                `json:"log_driver"`
+	LogDriverOptions  map[string]string     `json:"log_driver_options"`
+
+	// runtime options: zero values use the image's or Docker's defaults
+	Command          []string          `json:"command"`
+	EntryPoint       []string          `json:"entry_point"`
+	WorkingDirectory string            `json:"working_directory"`
+	User             string            `json:"user"`
+	Ulimits          []Ulimit          `json:"ulimits"`
+	DockerLabels     map[string]string `json:"docker_labels"`
+	ExtraHosts       map[string]string `json:"extra_hosts"` // hostname -> IP address
+	DNSServers       []string          `json:"dns_servers"`
+	StopTimeout      uint64            `json:"stop_timeout"` // seconds
+}
+
+type Ulimit struct {
+	Name      string `json:"name"`
+	SoftLimit uint64 `json:"soft_limit"`
+	HardLimit uint64 `json:"hard_limit"`
+}
+
+// HealthCheck is the container health check that ECS runs in the container. Times are in seconds.
+type HealthCheck struct {
+	Command     []string `json:"command"`
+	Interval    uint64   `json:"interval"`
+	Timeout     uint64   `json:"timeout"`
+	Retries     uint16   `json:"retries"`
+	StartPeriod uint64   `json:"start_period"`
+}
+
+type ContainerDependency struct {
+	ContainerName string `json:"container_name"`
+	Condition     string `json:"condition"`
+}
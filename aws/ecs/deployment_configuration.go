@@ -0,0 +1,10 @@
+package ecs
+
+// DeploymentConfiguration controls how many tasks can run (max percent) and must stay healthy (min healthy percent)
+// during a deployment, and whether ECS deployment circuit breaker stops (and optionally rolls back) failing deployments.
+type DeploymentConfiguration struct {
+	MaxPercent             uint16
+	MinHealthyPercent      uint16
+	CircuitBreaker         bool
+	CircuitBreakerRollback bool
+}
@@ -0,0 +1,18 @@
+package ecs
+
+// Placement is the task placement configuration of an ECS Service. Strategies are evaluated in order,
+// and all constraints must be satisfied.
+type Placement struct {
+	Strategies  []PlacementStrategy
+	Constraints []PlacementConstraint
+}
+
+type PlacementStrategy struct {
+	Type  string `json:"type"`
+	Field string `json:"field"`
+}
+
+type PlacementConstraint struct {
+	Type       string `json:"type"`
+	Expression string `json:"expression"`
+}
@@ -2,5 +2,6 @@ package ecs
 
 type PortMapping struct {
 	ContainerPort uint16 `json:"container_port"`
+	HostPort      uint16 `json:"host_port"`
 	Protocol      string `json:"protocol"`
 }
@@ -0,0 +1,10 @@
+package ecs
+
+// TaskDefinitionInput is a new revision of the ECS Task Definition to register.
+type TaskDefinitionInput struct {
+	Family           string
+	ExecutionRoleARN string // optional: role that ECS uses to pull secrets
+	TaskRoleARN      string // optional: role that the containers run with
+	Containers       []*ContainerDefinition
+	Volumes          []*Volume
+}
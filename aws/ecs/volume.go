@@ -0,0 +1,31 @@
+package ecs
+
+// Volume is a data volume of the ECS Task Definition. Exactly one of HostPath, Docker, and EFS is used:
+// if none is set, the volume is a scratch volume on the container instance that lives as long as the task.
+type Volume struct {
+	Name     string        `json:"name"`
+	HostPath string        `json:"host_path"`
+	Docker   *DockerVolume `json:"docker"`
+	EFS      *EFSVolume    `json:"efs"`
+}
+
+type DockerVolume struct {
+	Driver        string            `json:"driver"`
+	DriverOptions map[string]string `json:"driver_options"`
+	Scope         string            `json:"scope"` // "task" or "shared"
+	Autoprovision bool              `json:"autoprovision"`
+}
+
+type EFSVolume struct {
+	FileSystemID      string `json:"file_system_id"`
+	RootDirectory     string `json:"root_directory"`
+	AccessPointID     string `json:"access_point_id"`
+	TransitEncryption bool   `json:"transit_encryption"`
+	IAM               bool   `json:"iam"`
+}
+
+type MountPoint struct {
+	SourceVolume  string `json:"source_volume"`
+	ContainerPath string `json:"container_path"`
+	ReadOnly      bool   `json:"read_only"`
+}
@@ -189,6 +189,19 @@ func (c *Client) UpdateTargetGroupHealthCheck(targetGroupARN string, healthCheck
 	return err
 }
 
+func (c *Client) RetrieveTargetHealth(targetGroupARN string) ([]*_elb.TargetHealthDescription, error) {
+	params := &_elb.DescribeTargetHealthInput{
+		TargetGroupArn: _aws.String(targetGroupARN),
+	}
+
+	res, err := c.svc.DescribeTargetHealth(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.TargetHealthDescriptions, nil
+}
+
 func (c *Client) RetrieveTargetGroupByName(targetGroupName string) (*_elb.TargetGroup, error) {
 	params := &_elb.DescribeTargetGroupsInput{
 		Names: _aws.StringSlice([]string{targetGroupName}),
@@ -282,3 +295,84 @@ func (c *Client) RetrieveTags(resourceARN string) (map[string]string, error) {
 
 	return tags, nil
 }
+
+// UpdateListenerTargetGroup changes the default action of the listener to forward to the target group.
+func (c *Client) UpdateListenerTargetGroup(listenerARN, targetGroupARN string) error {
+	params := &_elb.ModifyListenerInput{
+		ListenerArn: _aws.String(listenerARN),
+		DefaultActions: []*_elb.Action{
+			{
+				TargetGroupArn: _aws.String(targetGroupARN),
+				Type:           _aws.String(_elb.ActionTypeEnumForward),
+			},
+		},
+	}
+
+	_, err := c.svc.ModifyListener(params)
+
+	return err
+}
+
+func (c *Client) RetrieveListenerRules(listenerARN string) ([]*_elb.Rule, error) {
+	rules := []*_elb.Rule{}
+	var marker *string
+
+	for {
+		params := &_elb.DescribeRulesInput{
+			Marker:      marker,
+			ListenerArn: _aws.String(listenerARN),
+		}
+
+		res, err := c.svc.DescribeRules(params)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, res.Rules...)
+
+		if utils.IsBlank(conv.S(res.NextMarker)) {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return rules, nil
+}
+
+// CreateHTTPHeaderRule adds a listener rule that forwards requests with the HTTP header to the target group.
+func (c *Client) CreateHTTPHeaderRule(listenerARN, targetGroupARN string, priority int64, headerName, headerValue string) error {
+	params := &_elb.CreateRuleInput{
+		ListenerArn: _aws.String(listenerARN),
+		Priority:    _aws.Int64(priority),
+		Conditions: []*_elb.RuleCondition{
+			{
+				Field: _aws.String("http-header"),
+				HttpHeaderConfig: &_elb.HttpHeaderConditionConfig{
+					HttpHeaderName: _aws.String(headerName),
+					Values:         _aws.StringSlice([]string{headerValue}),
+				},
+			},
+		},
+		Actions: []*_elb.Action{
+			{
+				TargetGroupArn: _aws.String(targetGroupARN),
+				Type:           _aws.String(_elb.ActionTypeEnumForward),
+			},
+		},
+	}
+
+	_, err := c.svc.CreateRule(params)
+
+	return err
+}
+
+func (c *Client) DeleteRule(ruleARN string) error {
+	params := &_elb.DeleteRuleInput{
+		RuleArn: _aws.String(ruleARN),
+	}
+
+	_, err := c.svc.DeleteRule(params)
+
+	return err
+}
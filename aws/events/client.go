@@ -0,0 +1,199 @@
+package events
+
+import (
+	"encoding/json"
+
+	_aws "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	_events "github.com/aws/aws-sdk-go/service/cloudwatchevents"
+)
+
+type Client struct {
+	svc *_events.CloudWatchEvents
+}
+
+func New(session *session.Session, config *_aws.Config) *Client {
+	return &Client{
+		svc: _events.New(session, config),
+	}
+}
+
+// ECSTaskTarget runs tasks of the ECS Task Definition on the ECS Cluster when the rule triggers. If Command is
+// not empty, it overrides the command of the container.
+type ECSTaskTarget struct {
+	ClusterARN        string
+	TaskDefinitionARN string
+	RoleARN           string // role that CloudWatch Events runs the tasks with
+	TaskCount         uint16
+	ContainerName     string
+	Command           []string
+}
+
+func (c *Client) ListRuleNames(namePrefix string) ([]string, error) {
+	var nextToken *string
+	ruleNames := []string{}
+
+	for {
+		params := &_events.ListRulesInput{
+			NamePrefix: _aws.String(namePrefix),
+			NextToken:  nextToken,
+		}
+
+		res, err := c.svc.ListRules(params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rule := range res.Rules {
+			ruleNames = append(ruleNames, _aws.StringValue(rule.Name))
+		}
+
+		if res.NextToken == nil {
+			break
+		} else {
+			nextToken = res.NextToken
+		}
+	}
+
+	return ruleNames, nil
+}
+
+// RetrieveRule returns nil if the rule does not exist.
+func (c *Client) RetrieveRule(ruleName string) (*_events.DescribeRuleOutput, error) {
+	params := &_events.DescribeRuleInput{
+		Name: _aws.String(ruleName),
+	}
+
+	res, err := c.svc.DescribeRule(params)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			if awsErr.Code() == _events.ErrCodeResourceNotFoundException {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// PutScheduleRule creates or updates the rule that triggers on the schedule expression.
+func (c *Client) PutScheduleRule(ruleName, scheduleExpression, description string) error {
+	params := &_events.PutRuleInput{
+		Name:               _aws.String(ruleName),
+		ScheduleExpression: _aws.String(scheduleExpression),
+		Description:        _aws.String(description),
+		State:              _aws.String(_events.RuleStateEnabled),
+	}
+
+	_, err := c.svc.PutRule(params)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PutECSTaskTarget creates or updates the target of the rule with the target ID.
+func (c *Client) PutECSTaskTarget(ruleName, targetID string, target *ECSTaskTarget) error {
+	t := &_events.Target{
+		Id:      _aws.String(targetID),
+		Arn:     _aws.String(target.ClusterARN),
+		RoleArn: _aws.String(target.RoleARN),
+		EcsParameters: &_events.EcsParameters{
+			TaskDefinitionArn: _aws.String(target.TaskDefinitionARN),
+			TaskCount:         _aws.Int64(int64(target.TaskCount)),
+		},
+	}
+	if len(target.Command) > 0 {
+		input, err := json.Marshal(map[string]interface{}{
+			"containerOverrides": []map[string]interface{}{
+				{"name": target.ContainerName, "command": target.Command},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		t.Input = _aws.String(string(input))
+	}
+
+	return c.PutTargets(ruleName, []*_events.Target{t})
+}
+
+// PutTargets creates or updates the targets of the rule.
+func (c *Client) PutTargets(ruleName string, targets []*_events.Target) error {
+	params := &_events.PutTargetsInput{
+		Rule:    _aws.String(ruleName),
+		Targets: targets,
+	}
+
+	res, err := c.svc.PutTargets(params)
+	if err != nil {
+		return err
+	}
+	if len(res.FailedEntries) > 0 {
+		return awserr.New(_aws.StringValue(res.FailedEntries[0].ErrorCode), _aws.StringValue(res.FailedEntries[0].ErrorMessage), nil)
+	}
+
+	return nil
+}
+
+func (c *Client) ListTargets(ruleName string) ([]*_events.Target, error) {
+	var nextToken *string
+	targets := []*_events.Target{}
+
+	for {
+		params := &_events.ListTargetsByRuleInput{
+			Rule:      _aws.String(ruleName),
+			NextToken: nextToken,
+		}
+
+		res, err := c.svc.ListTargetsByRule(params)
+		if err != nil {
+			return nil, err
+		}
+
+		targets = append(targets, res.Targets...)
+
+		if res.NextToken == nil {
+			break
+		} else {
+			nextToken = res.NextToken
+		}
+	}
+
+	return targets, nil
+}
+
+// DeleteRule removes all targets of the rule, and deletes the rule.
+func (c *Client) DeleteRule(ruleName string) error {
+	targets, err := c.ListTargets(ruleName)
+	if err != nil {
+		return err
+	}
+	if len(targets) > 0 {
+		targetIDs := []*string{}
+		for _, target := range targets {
+			targetIDs = append(targetIDs, target.Id)
+		}
+		params := &_events.RemoveTargetsInput{
+			Rule: _aws.String(ruleName),
+			Ids:  targetIDs,
+		}
+		if _, err := c.svc.RemoveTargets(params); err != nil {
+			return err
+		}
+	}
+
+	params := &_events.DeleteRuleInput{
+		Name: _aws.String(ruleName),
+	}
+
+	_, err = c.svc.DeleteRule(params)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package iam
 import (
 	"errors"
 	"net/http"
+	"net/url"
 
 	_aws "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -115,6 +116,56 @@ func (c *Client) ListRolePolicyNames(roleName string) ([]string, error) {
 	return policyNames, nil
 }
 
+func (c *Client) ListAttachedRolePolicyARNs(roleName string) ([]string, error) {
+	policyARNs := []string{}
+	var marker *string
+
+	for {
+		params := &_iam.ListAttachedRolePoliciesInput{
+			Marker:   marker,
+			RoleName: _aws.String(roleName),
+		}
+
+		res, err := c.svc.ListAttachedRolePolicies(params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range res.AttachedPolicies {
+			policyARNs = append(policyARNs, conv.S(p.PolicyArn))
+		}
+
+		if !conv.B(res.IsTruncated) {
+			break
+		}
+
+		marker = res.Marker
+	}
+
+	return policyARNs, nil
+}
+
+// RetrieveRolePolicyDocument returns the (URL-decoded) document of the inline policy, or an empty string
+// if the role does not have the policy.
+func (c *Client) RetrieveRolePolicyDocument(policyName, roleName string) (string, error) {
+	params := &_iam.GetRolePolicyInput{
+		PolicyName: _aws.String(policyName),
+		RoleName:   _aws.String(roleName),
+	}
+
+	res, err := c.svc.GetRolePolicy(params)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			if awsErr.Code() == _iam.ErrCodeNoSuchEntityException {
+				return "", nil
+			}
+		}
+		return "", err
+	}
+
+	return url.QueryUnescape(conv.S(res.PolicyDocument))
+}
+
 func (c *Client) DetachRolePolicy(policyARN, roleName string) error {
 	params := &_iam.DetachRolePolicyInput{
 		PolicyArn: _aws.String(policyARN),
@@ -213,3 +264,25 @@ func (c *Client) DeleteInstanceProfile(profileName string) error {
 
 	return err
 }
+
+func (c *Client) PutRolePolicy(policyName, policyDocument, roleName string) error {
+	if policyName == "" {
+		return errors.New("policyName is empty")
+	}
+	if policyDocument == "" {
+		return errors.New("policyDocument is empty")
+	}
+	if roleName == "" {
+		return errors.New("roleName is empty")
+	}
+
+	params := &_iam.PutRolePolicyInput{
+		PolicyName:     _aws.String(policyName),
+		PolicyDocument: _aws.String(policyDocument),
+		RoleName:       _aws.String(roleName),
+	}
+
+	_, err := c.svc.PutRolePolicy(params)
+
+	return err
+}
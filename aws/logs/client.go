@@ -2,6 +2,7 @@ package logs
 
 import (
 	_aws "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	_logs "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 )
@@ -57,3 +58,62 @@ func (c *Client) ListGroups(groupNamePrefix string) ([]*_logs.LogGroup, error) {
 
 	return groups, nil
 }
+
+// RetrieveLogEvents returns the log events of the stream from the oldest, starting after nextToken if not nil,
+// and the token to retrieve the next events with. It returns no events if the stream does not exist (yet).
+func (c *Client) RetrieveLogEvents(groupName, streamName string, nextToken *string) ([]*_logs.OutputLogEvent, *string, error) {
+	params := &_logs.GetLogEventsInput{
+		LogGroupName:  _aws.String(groupName),
+		LogStreamName: _aws.String(streamName),
+		StartFromHead: _aws.Bool(true),
+		NextToken:     nextToken,
+	}
+
+	res, err := c.svc.GetLogEvents(params)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok {
+			if awsErr.Code() == _logs.ErrCodeResourceNotFoundException {
+				return nil, nextToken, nil
+			}
+		}
+		return nil, nil, err
+	}
+
+	return res.Events, res.NextForwardToken, nil
+}
+
+// FilterLogEvents returns the log events of the group since startTime (milliseconds since epoch) that match the
+// filter pattern (all events if empty), reading up to maxPages pages starting at nextToken if not nil. Only the
+// streams in streamNames are searched unless it's empty. Events are in no particular order across the streams.
+// The returned token is nil if there are no more events.
+func (c *Client) FilterLogEvents(groupName string, streamNames []string, filterPattern string, startTime int64, nextToken *string, maxPages int) ([]*_logs.FilteredLogEvent, *string, error) {
+	events := []*_logs.FilteredLogEvent{}
+
+	for page := 0; page < maxPages; page++ {
+		params := &_logs.FilterLogEventsInput{
+			LogGroupName: _aws.String(groupName),
+			StartTime:    _aws.Int64(startTime),
+			NextToken:    nextToken,
+		}
+		if len(streamNames) > 0 {
+			params.LogStreamNames = _aws.StringSlice(streamNames)
+		}
+		if filterPattern != "" {
+			params.FilterPattern = _aws.String(filterPattern)
+		}
+
+		res, err := c.svc.FilterLogEvents(params)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		events = append(events, res.Events...)
+
+		nextToken = res.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return events, nextToken, nil
+}
@@ -1,6 +1,9 @@
 package aws
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+)
 
 func GetIAMInstanceProfileNameFromARN(arn string) string {
 	// format: "arn:aws:iam::865092420289:instance-profile/coldbrew_cluster1_instance_profile"
@@ -20,6 +23,16 @@ func GetECSTaskDefinitionFamilyAndRevisionFromARN(arn string) string {
 	return tokens[len(tokens)-1]
 }
 
+func GetECSTaskDefinitionRevisionFromARN(arn string) int64 {
+	// format: "arn:aws:ecs:us-west-2:865092420289:task-definition/echo:112"
+	tokens := strings.Split(arn, ":")
+	revision, err := strconv.ParseInt(tokens[len(tokens)-1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
+
 func GetECSContainerInstanceIDFromARN(arn string) string {
 	// format: "arn:aws:ecs:us-west-2:865092420289:container-instance/72b93c91-0572-4d9d-b3d6-6e5cc5a0d2be"
 	tokens := strings.Split(arn, "/")
@@ -28,3 +41,25 @@ func GetECSContainerInstanceIDFromARN(arn string) string {
 	}
 	return tokens[len(tokens)-1]
 }
+
+func GetECSTaskIDFromARN(arn string) string {
+	// format: "arn:aws:ecs:us-west-2:865092420289:task/coldbrew-cluster1/0cc43cdb3c0e4f0c9b1a2d5c3e1d7f2a"
+	//   (or "arn:aws:ecs:us-west-2:865092420289:task/0cc43cdb-3c0e-4f0c-9b1a-2d5c3e1d7f2a")
+	tokens := strings.Split(arn, "/")
+	if len(tokens) == 0 {
+		return ""
+	}
+	return tokens[len(tokens)-1]
+}
+
+func GetELBRequestCountResourceLabelFromARNs(loadBalancerARN, targetGroupARN string) string {
+	// format: "app/echo-elb/50dc6c495c0c9188/targetgroup/echo-elb-tg/943f017f100becff" from
+	//   "arn:aws:elasticloadbalancing:us-west-2:865092420289:loadbalancer/app/echo-elb/50dc6c495c0c9188" and
+	//   "arn:aws:elasticloadbalancing:us-west-2:865092420289:targetgroup/echo-elb-tg/943f017f100becff"
+	lbTokens := strings.SplitN(loadBalancerARN, ":loadbalancer/", 2)
+	tgTokens := strings.Split(targetGroupARN, ":")
+	if len(lbTokens) != 2 || len(tgTokens) == 0 {
+		return ""
+	}
+	return lbTokens[1] + "/" + tgTokens[len(tgTokens)-1]
+}
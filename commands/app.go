@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/config"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/flags"
+	"github.com/coldbrewcloud/coldbrew-cli/utils"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+// ResolveApp loads the app configuration file (conf is nil if the file does not exist), and returns the app
+// and cluster names. App and cluster names from the CLI flags override the configuration file.
+func ResolveApp(globalFlags *flags.GlobalFlags, appNameFlag, clusterNameFlag *string) (conf *config.Config, appName, clusterName string, err error) {
+	configFilePath, err := globalFlags.GetConfigFile()
+	if err != nil {
+		return nil, "", "", err
+	}
+	if utils.FileExists(configFilePath) {
+		configData, err := ioutil.ReadFile(configFilePath)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("Failed to read configuration file [%s]: %s", configFilePath, err.Error())
+		}
+		conf, err = config.Load(configData, conv.S(globalFlags.ConfigFileFormat), core.DefaultAppName(configFilePath), conv.S(globalFlags.Environment), filepath.Dir(configFilePath))
+		if err != nil {
+			return nil, "", "", err
+		}
+
+		appName = conv.S(conf.Name)
+		clusterName = conv.S(conf.ClusterName)
+	}
+
+	// app/cluster name from CLI will override configuration file
+	if !utils.IsBlank(conv.S(appNameFlag)) {
+		appName = conv.S(appNameFlag)
+	}
+	if !utils.IsBlank(conv.S(clusterNameFlag)) {
+		clusterName = conv.S(clusterNameFlag)
+	}
+
+	if utils.IsBlank(appName) {
+		return nil, "", "", errors.New("App name is required.")
+	}
+	if utils.IsBlank(clusterName) {
+		return nil, "", "", errors.New("Cluster name is required.")
+	}
+
+	return conf, appName, clusterName, nil
+}
+
+// IsAppConfig returns true if conf is the configuration of the app in the cluster (i.e. app and cluster names were
+// not overridden by the CLI flags).
+func IsAppConfig(conf *config.Config, appName, clusterName string) bool {
+	return conf != nil && appName == conv.S(conf.Name) && clusterName == conv.S(conf.ClusterName)
+}
+
+// AppECSServiceNames returns the names of the app's ECS Services, live one first. For blue/green deployments, it
+// also includes the idle ECS Service.
+func AppECSServiceNames(awsClient *aws.Client, conf *config.Config, appName, clusterName string) ([]string, error) {
+	if !IsAppConfig(conf, appName, clusterName) || !conv.B(conf.LoadBalancer.BlueGreen.Enabled) {
+		return []string{core.DefaultECSServiceName(appName)}, nil
+	}
+
+	state, err := RetrieveBlueGreenState(awsClient, conf)
+	if err != nil {
+		return nil, err
+	}
+	return []string{state.Live.ECSServiceName, state.Idle.ECSServiceName}, nil
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"fmt"
+
+	_aas "github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/config"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+// UpdateAutoScaling registers the ECS Service as a scalable target and creates, updates, or deletes its target
+// tracking scaling policies to match the configuration. If auto scaling is disabled, the scalable target is
+// deregistered if it exists.
+func UpdateAutoScaling(awsClient *aws.Client, conf *config.Config, ecsClusterName, ecsServiceName string) error {
+	if !conv.B(conf.AutoScaling.Enabled) {
+		return DeregisterAutoScaling(awsClient, ecsClusterName, ecsServiceName)
+	}
+
+	minUnits, maxUnits := conv.U16(conf.AutoScaling.MinUnits), conv.U16(conf.AutoScaling.MaxUnits)
+	console.UpdatingResource(fmt.Sprintf("Registering auto scaling target (%d-%d units) for ECS Service", minUnits, maxUnits), ecsServiceName, false)
+	if err := awsClient.ApplicationAutoScaling().RegisterECSServiceScalableTarget(ecsClusterName, ecsServiceName, minUnits, maxUnits); err != nil {
+		return fmt.Errorf("Failed to register auto scaling target for ECS Service [%s]: %s", ecsServiceName, err.Error())
+	}
+
+	currentPolicies, err := awsClient.ApplicationAutoScaling().RetrieveECSServiceScalingPolicies(ecsClusterName, ecsServiceName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve auto scaling policies for ECS Service [%s]: %s", ecsServiceName, err.Error())
+	}
+	currentPolicyNames := make(map[string]bool)
+	for _, p := range currentPolicies {
+		currentPolicyNames[conv.S(p.PolicyName)] = true
+	}
+
+	policies := []struct {
+		metric     string
+		metricType string
+		target     uint16
+	}{
+		{"cpu", _aas.MetricTypeEcsserviceAverageCpuutilization, conv.U16(conf.AutoScaling.CPUTarget)},
+		{"memory", _aas.MetricTypeEcsserviceAverageMemoryUtilization, conv.U16(conf.AutoScaling.MemoryTarget)},
+		{"requests", _aas.MetricTypeAlbrequestCountPerTarget, conv.U16(conf.AutoScaling.RequestsTarget)},
+	}
+	for _, p := range policies {
+		policyName := core.DefaultECSServiceScalingPolicyName(p.metric)
+
+		if p.target == 0 {
+			if currentPolicyNames[policyName] {
+				console.RemovingResource("Deleting auto scaling policy", policyName, false)
+				if err := awsClient.ApplicationAutoScaling().DeleteECSServiceScalingPolicy(ecsClusterName, ecsServiceName, policyName); err != nil {
+					return fmt.Errorf("Failed to delete auto scaling policy [%s]: %s", policyName, err.Error())
+				}
+			}
+			continue
+		}
+
+		resourceLabel := ""
+		if p.metricType == _aas.MetricTypeAlbrequestCountPerTarget {
+			resourceLabel, err = elbRequestCountResourceLabel(awsClient, ecsClusterName, ecsServiceName)
+			if err != nil {
+				return err
+			}
+		}
+
+		console.UpdatingResource(fmt.Sprintf("Updating auto scaling policy (%s target: %d)", p.metric, p.target), policyName, false)
+		err := awsClient.ApplicationAutoScaling().PutECSServiceTargetTrackingPolicy(
+			ecsClusterName, ecsServiceName, policyName, p.metricType, resourceLabel, float64(p.target))
+		if err != nil {
+			return fmt.Errorf("Failed to update auto scaling policy [%s]: %s", policyName, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// DeregisterAutoScaling deregisters the ECS Service from Application Auto Scaling (which deletes its scaling
+// policies too). It does nothing if the ECS Service is not registered.
+func DeregisterAutoScaling(awsClient *aws.Client, ecsClusterName, ecsServiceName string) error {
+	scalableTarget, err := awsClient.ApplicationAutoScaling().RetrieveECSServiceScalableTarget(ecsClusterName, ecsServiceName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve auto scaling target for ECS Service [%s]: %s", ecsServiceName, err.Error())
+	}
+	if scalableTarget == nil {
+		return nil
+	}
+
+	console.RemovingResource("Deregistering auto scaling target for ECS Service", ecsServiceName, false)
+	if err := awsClient.ApplicationAutoScaling().DeregisterECSServiceScalableTarget(ecsClusterName, ecsServiceName); err != nil {
+		return fmt.Errorf("Failed to deregister auto scaling target for ECS Service [%s]: %s", ecsServiceName, err.Error())
+	}
+
+	return nil
+}
+
+// elbRequestCountResourceLabel returns the resource label of the ELB Load Balancer and Target Group that
+// the ECS Service is registered to.
+func elbRequestCountResourceLabel(awsClient *aws.Client, ecsClusterName, ecsServiceName string) (string, error) {
+	ecsService, err := awsClient.ECS().RetrieveService(ecsClusterName, ecsServiceName)
+	if err != nil {
+		return "", fmt.Errorf("Failed to retrieve ECS Service [%s/%s]: %s", ecsClusterName, ecsServiceName, err.Error())
+	}
+	if ecsService == nil || len(ecsService.LoadBalancers) == 0 {
+		return "", fmt.Errorf("ECS Service [%s] is not registered to an ELB Target Group.", ecsServiceName)
+	}
+
+	elbTargetGroupARN := conv.S(ecsService.LoadBalancers[0].TargetGroupArn)
+	elbTargetGroup, err := awsClient.ELB().RetrieveTargetGroup(elbTargetGroupARN)
+	if err != nil {
+		return "", fmt.Errorf("Failed to retrieve ELB Target Group [%s]: %s", elbTargetGroupARN, err.Error())
+	}
+	if elbTargetGroup == nil || len(elbTargetGroup.LoadBalancerArns) == 0 {
+		return "", fmt.Errorf("ELB Target Group [%s] is not associated with an ELB Load Balancer.", elbTargetGroupARN)
+	}
+
+	return aws.GetELBRequestCountResourceLabelFromARNs(conv.S(elbTargetGroup.LoadBalancerArns[0]), elbTargetGroupARN), nil
+}
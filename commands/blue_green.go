@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"fmt"
+
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
+	_elb "github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/config"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+// BlueGreenSlot is one of the two ECS Service and ELB Target Group pairs used in blue/green deployments.
+type BlueGreenSlot struct {
+	ECSServiceName     string
+	ECSService         *_ecs.Service // nil if not created (or not active)
+	ELBTargetGroupName string
+	ELBTargetGroup     *_elb.TargetGroup // nil if not created
+}
+
+// BlueGreenState tells which slot is live (receives traffic from the ELB Load Balancer listeners) and which is idle.
+type BlueGreenState struct {
+	ELBLoadBalancer *_elb.LoadBalancer // nil if not created
+	Listeners       []*_elb.Listener   // listeners forwarding to the live slot
+	Live            *BlueGreenSlot
+	Idle            *BlueGreenSlot
+}
+
+// RetrieveBlueGreenState returns the blue/green deployment state of the app. If no listener forwards to
+// either target group yet (e.g. first deployment), blue ECS Service is considered live.
+func RetrieveBlueGreenState(awsClient *aws.Client, conf *config.Config) (*BlueGreenState, error) {
+	ecsClusterName := core.DefaultECSClusterName(conv.S(conf.ClusterName))
+	blue := &BlueGreenSlot{
+		ECSServiceName:     core.DefaultECSServiceName(conv.S(conf.Name)),
+		ELBTargetGroupName: conv.S(conf.AWS.ELBTargetGroupName),
+	}
+	green := &BlueGreenSlot{
+		ECSServiceName:     core.DefaultECSGreenServiceName(conv.S(conf.Name)),
+		ELBTargetGroupName: conv.S(conf.AWS.ELBGreenTargetGroupName),
+	}
+
+	for _, slot := range []*BlueGreenSlot{blue, green} {
+		ecsService, err := awsClient.ECS().RetrieveService(ecsClusterName, slot.ECSServiceName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve ECS Service [%s/%s]: %s", ecsClusterName, slot.ECSServiceName, err.Error())
+		}
+		if ecsService != nil && conv.S(ecsService.Status) == "ACTIVE" {
+			slot.ECSService = ecsService
+		}
+
+		slot.ELBTargetGroup, err = awsClient.ELB().RetrieveTargetGroupByName(slot.ELBTargetGroupName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve ELB Target Group [%s]: %s", slot.ELBTargetGroupName, err.Error())
+		}
+	}
+
+	state := &BlueGreenState{Live: blue, Idle: green}
+
+	elbLoadBalancerName := conv.S(conf.AWS.ELBLoadBalancerName)
+	elbLoadBalancer, err := awsClient.ELB().RetrieveLoadBalancerByName(elbLoadBalancerName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve ELB Load Balancer [%s]: %s", elbLoadBalancerName, err.Error())
+	}
+	if elbLoadBalancer == nil {
+		return state, nil
+	}
+	state.ELBLoadBalancer = elbLoadBalancer
+
+	listeners, err := awsClient.ELB().RetrieveLoadBalancerListeners(conv.S(elbLoadBalancer.LoadBalancerArn))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve listeners for ELB Load Balancer [%s]: %s", elbLoadBalancerName, err.Error())
+	}
+	blueListeners, greenListeners := listenersForwardingTo(listeners, blue), listenersForwardingTo(listeners, green)
+	if len(greenListeners) > 0 {
+		if len(blueListeners) > 0 {
+			return nil, fmt.Errorf("ELB Load Balancer [%s] has listeners to both ELB Target Groups [%s] and [%s].",
+				elbLoadBalancerName, blue.ELBTargetGroupName, green.ELBTargetGroupName)
+		}
+		state.Live, state.Idle = green, blue
+		state.Listeners = greenListeners
+	} else {
+		state.Listeners = blueListeners
+	}
+
+	return state, nil
+}
+
+func listenersForwardingTo(listeners []*_elb.Listener, slot *BlueGreenSlot) []*_elb.Listener {
+	forwarding := []*_elb.Listener{}
+	if slot.ELBTargetGroup == nil {
+		return forwarding
+	}
+
+	for _, l := range listeners {
+		for _, a := range l.DefaultActions {
+			if conv.S(a.TargetGroupArn) == conv.S(slot.ELBTargetGroup.TargetGroupArn) {
+				forwarding = append(forwarding, l)
+				break
+			}
+		}
+	}
+
+	return forwarding
+}
+
+// SwitchBlueGreenListeners changes the listeners so that they forward to the idle slot, and then swaps
+// the live and idle slots of the state.
+func SwitchBlueGreenListeners(awsClient *aws.Client, state *BlueGreenState) error {
+	if state.Idle.ELBTargetGroup == nil {
+		return fmt.Errorf("ELB Target Group [%s] was not found.", state.Idle.ELBTargetGroupName)
+	}
+	targetGroupARN := conv.S(state.Idle.ELBTargetGroup.TargetGroupArn)
+
+	for _, l := range state.Listeners {
+		console.UpdatingResource(fmt.Sprintf("Switching ELB Listener (%s:%d) to ELB Target Group [%s]",
+			conv.S(l.Protocol), conv.I64(l.Port), state.Idle.ELBTargetGroupName),
+			conv.S(state.ELBLoadBalancer.LoadBalancerName), false)
+		if err := awsClient.ELB().UpdateListenerTargetGroup(conv.S(l.ListenerArn), targetGroupARN); err != nil {
+			return fmt.Errorf("Failed to update ELB Listener [%s]: %s", conv.S(l.ListenerArn), err.Error())
+		}
+	}
+
+	state.Live, state.Idle = state.Idle, state.Live
+	return nil
+}
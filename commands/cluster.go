@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+// CheckClusterAvailability returns an error if the ECS Cluster or the ECS Service Role of the
+// cluster does not exist.
+func CheckClusterAvailability(awsClient *aws.Client, clusterName string) error {
+	// check ECS cluster
+	ecsClusterName := core.DefaultECSClusterName(clusterName)
+	ecsCluster, err := awsClient.ECS().RetrieveCluster(ecsClusterName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve ECS Cluster [%s]: %s", ecsClusterName, err.Error())
+	}
+	if ecsCluster == nil || conv.S(ecsCluster.Status) == "INACTIVE" {
+		return fmt.Errorf("ECS Cluster [%s] not found", ecsClusterName)
+	}
+
+	// check ECS service role
+	ecsServiceRoleName := core.DefaultECSServiceRoleName(clusterName)
+	ecsServiceRole, err := awsClient.IAM().RetrieveRole(ecsServiceRoleName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve IAM Role [%s]: %s", ecsServiceRoleName, err.Error())
+	}
+	if ecsServiceRole == nil {
+		return fmt.Errorf("IAM Role [%s] not found", ecsServiceRoleName)
+	}
+
+	return nil
+}
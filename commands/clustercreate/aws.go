@@ -100,9 +100,16 @@ func (c *Command) retrieveDefaultECSContainerInstancesImageID(region string) str
 	return ""
 }
 
+// getDefaultInstanceUserData returns the user data of the ECS Container Instances. Containers are blocked from
+// reaching the instance metadata service, so that they cannot use the credentials of the instance role; apps get
+// their own permissions through the task role instead.
 func (c *Command) getDefaultInstanceUserData(ecsClusterName string) string {
 	userData := fmt.Sprintf(`#!/bin/bash
-echo ECS_CLUSTER=%s >> /etc/ecs/ecs.config`, ecsClusterName)
+echo ECS_CLUSTER=%s >> /etc/ecs/ecs.config
+echo ECS_ENABLE_TASK_IAM_ROLE=true >> /etc/ecs/ecs.config
+echo ECS_AWSVPC_BLOCK_IMDS=true >> /etc/ecs/ecs.config
+iptables --insert FORWARD 1 --in-interface docker0 --destination 169.254.169.254/32 --jump DROP
+service iptables save`, ecsClusterName)
 	return base64.StdEncoding.EncodeToString([]byte(userData))
 }
 
@@ -111,7 +118,7 @@ func (c *Command) createDefaultInstanceProfile(profileName string) (string, erro
 	if err != nil {
 		return "", fmt.Errorf("Failed to create IAM Role [%s]: %s", profileName, err.Error())
 	}
-	if err := c.awsClient.IAM().AttachRolePolicy(core.AdministratorAccessPolicyARN, profileName); err != nil {
+	if err := c.awsClient.IAM().AttachRolePolicy(core.ECSContainerInstanceRolePolicyARN, profileName); err != nil {
 		return "", fmt.Errorf("Failed to attach policy to IAM Role [%s]: %s", profileName, err.Error())
 	}
 
@@ -70,8 +70,15 @@ func (c *Command) deleteDefaultInstanceProfile(profileName string) error {
 		return fmt.Errorf("Failed to remove IAM Role [%s] from Instance Profile [%s]: %s", profileName, profileName, err.Error())
 	}
 
-	if err := c.awsClient.IAM().DetachRolePolicy(core.AdministratorAccessPolicyARN, profileName); err != nil {
-		return fmt.Errorf("Failed to detach Administrator Access Policy from IAM Role [%s]: %s", profileName, err.Error())
+	// clusters created by older versions have AdministratorAccess attached instead of the ECS instance role policy
+	policyARNs, err := c.awsClient.IAM().ListAttachedRolePolicyARNs(profileName)
+	if err != nil {
+		return fmt.Errorf("Failed to list policies of IAM Role [%s]: %s", profileName, err.Error())
+	}
+	for _, policyARN := range policyARNs {
+		if err := c.awsClient.IAM().DetachRolePolicy(policyARN, profileName); err != nil {
+			return fmt.Errorf("Failed to detach policy [%s] from IAM Role [%s]: %s", policyARN, profileName, err.Error())
+		}
 	}
 
 	if err := c.awsClient.IAM().DeleteRole(profileName); err != nil {
@@ -1,11 +1,13 @@
 package clusterstatus
 
 import (
-	"strings"
-
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 
 	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/config"
 	"github.com/coldbrewcloud/coldbrew-cli/console"
 	"github.com/coldbrewcloud/coldbrew-cli/core"
 	"github.com/coldbrewcloud/coldbrew-cli/flags"
@@ -49,8 +51,28 @@ func (c *Command) Run() error {
 			fmt.Errorf("Invalid cluster name [%s]", clusterName), "https://github.com/coldbrewcloud/coldbrew-cli/wiki/Configuration-File#cluster"))
 	}
 
+	// app configuration (optional): used to report whether the app fits in the cluster
+	conf, err := c.loadAppConfig(clusterName)
+	if err != nil {
+		console.Warning(fmt.Sprintf("Skipping app resource report: %s", err.Error()))
+		conf = nil
+	}
+	var appTaskCPU, appTaskMemory uint64
+	if conf != nil {
+		appTaskCPU, appTaskMemory, err = conf.TaskResources()
+		if err != nil {
+			console.Warning(fmt.Sprintf("Skipping app resource report: %s", err.Error()))
+			conf = nil
+		}
+	}
+
 	console.Info("Cluster")
 	console.DetailWithResource("Name", clusterName)
+	if conf != nil {
+		console.DetailWithResource("App", conv.S(conf.Name))
+		console.DetailWithResource("  Task (CPU/memory reserved)", fmt.Sprintf("%.2f/%dM",
+			float64(appTaskCPU)/1024.0, appTaskMemory))
+	}
 
 	// AWS env
 	console.Info("AWS")
@@ -176,6 +198,7 @@ func (c *Command) Run() error {
 			return console.ExitWithErrorString("Failed to retrieve EC2 Instances: %s", err.Error())
 		}
 
+		var appUnitsThatFit uint64
 		for _, ci := range containerInstances {
 			console.Info("ECS Container Instance")
 
@@ -213,6 +236,14 @@ func (c *Command) Run() error {
 				float64(remainingCPU)/1024.0, float64(registeredCPU)/1024.0))
 			console.DetailWithResource("Memory (remaining/registered)", fmt.Sprintf("%dM/%dM,",
 				remainingMemory, registeredMemory))
+			if conf != nil {
+				units := config.UnitsThatFit(appTaskCPU, appTaskMemory, remainingCPU, remainingMemory)
+				if conv.B(ci.AgentConnected) && conv.S(ci.Status) == "ACTIVE" {
+					appUnitsThatFit += units
+				}
+				console.DetailWithResource(fmt.Sprintf("App [%s] units that fit", conv.S(conf.Name)),
+					fmt.Sprintf("%d", units))
+			}
 
 			console.DetailWithResource("EC2 Instance ID", conv.S(ci.Ec2InstanceId))
 			for _, ei := range ec2Instances {
@@ -227,7 +258,47 @@ func (c *Command) Run() error {
 				}
 			}
 		}
+
+		if conf != nil {
+			console.Info("App Placement")
+			if appUnitsThatFit == 0 {
+				console.DetailWithResourceNote(fmt.Sprintf("App [%s] units that fit", conv.S(conf.Name)),
+					"0", "(does not fit)", true)
+			} else {
+				console.DetailWithResource(fmt.Sprintf("App [%s] units that fit", conv.S(conf.Name)),
+					fmt.Sprintf("%d", appUnitsThatFit))
+			}
+		}
 	}
 
 	return nil
 }
+
+// loadAppConfig loads the app configuration file if it exists and the app belongs to the cluster.
+// It returns nil if there's no such app configuration.
+func (c *Command) loadAppConfig(clusterName string) (*config.Config, error) {
+	configFilePath, err := c.globalFlags.GetConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if !utils.FileExists(configFilePath) {
+		return nil, nil
+	}
+
+	configData, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read configuration file [%s]: %s", configFilePath, err.Error())
+	}
+	conf, err := config.Load(configData, conv.S(c.globalFlags.ConfigFileFormat), core.DefaultAppName(configFilePath), conv.S(c.globalFlags.Environment), filepath.Dir(configFilePath))
+	if err != nil {
+		return nil, err
+	}
+	if conv.S(conf.ClusterName) != clusterName {
+		return nil, nil
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
@@ -2,7 +2,6 @@ package delete
 
 import (
 	"fmt"
-	"io/ioutil"
 	"strings"
 	"time"
 
@@ -11,7 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/coldbrewcloud/coldbrew-cli/aws"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/ec2"
-	"github.com/coldbrewcloud/coldbrew-cli/config"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
 	"github.com/coldbrewcloud/coldbrew-cli/console"
 	"github.com/coldbrewcloud/coldbrew-cli/core"
 	"github.com/coldbrewcloud/coldbrew-cli/flags"
@@ -40,42 +39,11 @@ func (c *Command) Init(ka *kingpin.Application, globalFlags *flags.GlobalFlags)
 func (c *Command) Run() error {
 	c.awsClient = c.globalFlags.GetAWSClient()
 
-	appName := ""
-	clusterName := ""
-
 	// app configuration
-	configFilePath, err := c.globalFlags.GetConfigFile()
+	_, appName, clusterName, err := commands.ResolveApp(c.globalFlags, c.commandFlags.AppName, c.commandFlags.ClusterName)
 	if err != nil {
 		return console.ExitWithError(err)
 	}
-	if utils.FileExists(configFilePath) {
-		configData, err := ioutil.ReadFile(configFilePath)
-		if err != nil {
-			return console.ExitWithErrorString("Failed to read configuration file [%s]: %s", configFilePath, err.Error())
-		}
-		conf, err := config.Load(configData, conv.S(c.globalFlags.ConfigFileFormat), core.DefaultAppName(configFilePath))
-		if err != nil {
-			return console.ExitWithError(err)
-		}
-
-		appName = conv.S(conf.Name)
-		clusterName = conv.S(conf.ClusterName)
-	}
-
-	// app/cluster name from CLI will override configuration file
-	if !utils.IsBlank(conv.S(c.commandFlags.AppName)) {
-		appName = conv.S(c.commandFlags.AppName)
-	}
-	if !utils.IsBlank(conv.S(c.commandFlags.ClusterName)) {
-		clusterName = conv.S(c.commandFlags.ClusterName)
-	}
-
-	if utils.IsBlank(appName) {
-		return console.ExitWithErrorString("App name is required.")
-	}
-	if utils.IsBlank(clusterName) {
-		return console.ExitWithErrorString("Cluster name is required.")
-	}
 
 	console.Info("Determining AWS resources that need to be deleted...")
 
@@ -92,6 +60,32 @@ func (c *Command) Run() error {
 	} else {
 		return console.ExitWithErrorString("ECS Service [%s/%s] was not found.", ecsClusterName, ecsServiceName)
 	}
+	ecsServicesToDelete := []*ecs.Service{ecsServiceToDelete}
+
+	// second ECS Service (blue/green deployment)
+	ecsGreenServiceName := core.DefaultECSGreenServiceName(appName)
+	ecsGreenService, err := c.awsClient.ECS().RetrieveService(ecsClusterName, ecsGreenServiceName)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to retrieve ECS Service [%s/%s]: %s", ecsClusterName, ecsGreenServiceName, err.Error())
+	}
+	if ecsGreenService != nil && conv.S(ecsGreenService.Status) == "ACTIVE" {
+		console.DetailWithResource("ECS Service", ecsGreenServiceName)
+		ecsServicesToDelete = append(ecsServicesToDelete, ecsGreenService)
+	}
+
+	// Application Auto Scaling targets
+	ecsServiceNamesWithAutoScaling := []string{}
+	for _, ecsService := range ecsServicesToDelete {
+		name := conv.S(ecsService.ServiceName)
+		scalableTarget, err := c.awsClient.ApplicationAutoScaling().RetrieveECSServiceScalableTarget(ecsClusterName, name)
+		if err != nil {
+			return console.ExitWithErrorString("Failed to retrieve auto scaling target for ECS Service [%s]: %s", name, err.Error())
+		}
+		if scalableTarget != nil {
+			console.DetailWithResource("Auto Scaling Target", name)
+			ecsServiceNamesWithAutoScaling = append(ecsServiceNamesWithAutoScaling, name)
+		}
+	}
 
 	// identify ECR resources to delete
 	ecrRepositoryNameToDelete, err := c.identifyECRResourcesToDelete(appName, ecsServiceToDelete)
@@ -100,16 +94,66 @@ func (c *Command) Run() error {
 	}
 
 	// identify ELB resources to delete
-	elbLoadBalancersToDelete, elbTargetGroupsToDelete, elbLoadBalancerSecurityGroupsToDelete, err := c.identifyELBResourcesToDelete(ecsServiceToDelete)
+	elbLoadBalancersToDelete, elbTargetGroupsToDelete, elbLoadBalancerSecurityGroupsToDelete, err := c.identifyELBResourcesToDelete(ecsServicesToDelete)
 	if err != nil {
 		return console.ExitWithError(err)
 	}
 
+	// IAM Role for ECS task execution (secrets)
+	ecsTaskExecutionRoleNameToDelete := ""
+	ecsTaskExecutionRoleName := core.DefaultECSTaskExecutionRoleName(clusterName, appName)
+	ecsTaskExecutionRole, err := c.awsClient.IAM().RetrieveRole(ecsTaskExecutionRoleName)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to retrieve IAM Role [%s]: %s", ecsTaskExecutionRoleName, err.Error())
+	}
+	if ecsTaskExecutionRole != nil {
+		ecsTaskExecutionRoleNameToDelete = ecsTaskExecutionRoleName
+		console.DetailWithResource("IAM Role for ECS Tasks", ecsTaskExecutionRoleName)
+	}
+
+	// IAM Role that the app's containers run with
+	ecsTaskRoleNameToDelete := ""
+	ecsTaskRoleName := core.DefaultECSTaskRoleName(clusterName, appName)
+	ecsTaskRole, err := c.awsClient.IAM().RetrieveRole(ecsTaskRoleName)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to retrieve IAM Role [%s]: %s", ecsTaskRoleName, err.Error())
+	}
+	if ecsTaskRole != nil {
+		ecsTaskRoleNameToDelete = ecsTaskRoleName
+		console.DetailWithResource("IAM Role for app containers", ecsTaskRoleName)
+	}
+
+	// CloudWatch Events Rules of the scheduled tasks
+	rulePrefix := core.DefaultCloudWatchEventsRuleNamePrefix(clusterName, appName)
+	ruleNamesToDelete, err := c.awsClient.CloudWatchEvents().ListRuleNames(rulePrefix)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to list CloudWatch Events Rules [%s*]: %s", rulePrefix, err.Error())
+	}
+	for _, ruleName := range ruleNamesToDelete {
+		console.DetailWithResource("CloudWatch Events Rule", ruleName)
+	}
+
+	// IAM Role that CloudWatch Events runs the scheduled tasks with
+	ecsEventsRoleNameToDelete := ""
+	ecsEventsRoleName := core.DefaultECSEventsRoleName(clusterName, appName)
+	ecsEventsRole, err := c.awsClient.IAM().RetrieveRole(ecsEventsRoleName)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to retrieve IAM Role [%s]: %s", ecsEventsRoleName, err.Error())
+	}
+	if ecsEventsRole != nil {
+		ecsEventsRoleNameToDelete = ecsEventsRoleName
+		console.DetailWithResource("IAM Role for scheduled tasks", ecsEventsRoleName)
+	}
+
 	if ecsServiceToDelete == nil &&
 		len(elbLoadBalancersToDelete) == 0 &&
 		len(elbTargetGroupsToDelete) == 0 &&
 		len(elbLoadBalancerSecurityGroupsToDelete) == 0 &&
-		utils.IsBlank(ecrRepositoryNameToDelete) {
+		utils.IsBlank(ecrRepositoryNameToDelete) &&
+		utils.IsBlank(ecsTaskExecutionRoleNameToDelete) &&
+		utils.IsBlank(ecsTaskRoleNameToDelete) &&
+		len(ruleNamesToDelete) == 0 &&
+		utils.IsBlank(ecsEventsRoleNameToDelete) {
 		console.Info("Looks like everything's already cleaned up.")
 		return nil
 	}
@@ -123,12 +167,36 @@ func (c *Command) Run() error {
 
 	console.Blank()
 
-	// update ECS service (desired units => 0)
-	console.UpdatingResource("Updating ECS Service to stop all tasks", conv.S(ecsServiceToDelete.ServiceName), false)
-	_, err = c.awsClient.ECS().UpdateService(ecsClusterName, ecsServiceName, conv.S(ecsServiceToDelete.TaskDefinition), 0)
-	if err != nil {
-		// cannot continue with this error
-		return console.ExitWithError(err)
+	// delete CloudWatch Events Rules (otherwise scheduled tasks would keep running)
+	for _, ruleName := range ruleNamesToDelete {
+		console.RemovingResource("Deleting CloudWatch Events Rule", ruleName, false)
+		if err := c.awsClient.CloudWatchEvents().DeleteRule(ruleName); err != nil {
+			err = fmt.Errorf("Failed to delete CloudWatch Events Rule [%s]: %s", ruleName, err.Error())
+			if conv.B(c.commandFlags.ContinueOnError) {
+				console.Error(err.Error())
+			} else {
+				return console.ExitWithError(err)
+			}
+		}
+	}
+
+	// deregister auto scaling targets (otherwise ECS services would be scaled out again)
+	for _, name := range ecsServiceNamesWithAutoScaling {
+		console.RemovingResource("Deregistering auto scaling target for ECS Service", name, false)
+		if err := c.awsClient.ApplicationAutoScaling().DeregisterECSServiceScalableTarget(ecsClusterName, name); err != nil {
+			// cannot continue with this error
+			return console.ExitWithError(err)
+		}
+	}
+
+	// update ECS services (desired units => 0)
+	for _, ecsService := range ecsServicesToDelete {
+		console.UpdatingResource("Updating ECS Service to stop all tasks", conv.S(ecsService.ServiceName), false)
+		_, err = c.awsClient.ECS().UpdateService(ecsClusterName, conv.S(ecsService.ServiceName), conv.S(ecsService.TaskDefinition), conv.U16P(0), nil, nil)
+		if err != nil {
+			// cannot continue with this error
+			return console.ExitWithError(err)
+		}
 	}
 
 	// delete ELB Load Balancer
@@ -146,6 +214,15 @@ func (c *Command) Run() error {
 
 	// delete ELB Target Group {
 	for _, elbTargetGroupToDelete := range elbTargetGroupsToDelete {
+		// listener rules of load balancers that are not deleted (blue/green deployment)
+		if err := c.deleteELBListenerRules(elbTargetGroupToDelete, elbLoadBalancersToDelete); err != nil {
+			if conv.B(c.commandFlags.ContinueOnError) {
+				console.Error(err.Error())
+			} else {
+				return console.ExitWithError(err)
+			}
+		}
+
 		console.RemovingResource("Deleting ELB Target Group", conv.S(elbTargetGroupToDelete.TargetGroupName), true)
 
 		err := utils.RetryOnAWSErrorCode(func() error {
@@ -210,27 +287,75 @@ func (c *Command) Run() error {
 		}
 	}
 
-	// Delete ECS Service
-	console.RemovingResource("Deleting (and draining) ECS Service", conv.S(ecsServiceToDelete.ServiceName), true)
-	if err := c.awsClient.ECS().DeleteService(ecsClusterName, conv.S(ecsServiceToDelete.ServiceName)); err != nil {
-		if conv.B(c.commandFlags.ContinueOnError) {
-			console.Error(err.Error())
-		} else {
-			return console.ExitWithError(err)
+	// Delete ECS Services
+	for _, ecsService := range ecsServicesToDelete {
+		ecsServiceName := conv.S(ecsService.ServiceName)
+		console.RemovingResource("Deleting (and draining) ECS Service", ecsServiceName, true)
+		if err := c.awsClient.ECS().DeleteService(ecsClusterName, ecsServiceName); err != nil {
+			if conv.B(c.commandFlags.ContinueOnError) {
+				console.Error(err.Error())
+			} else {
+				return console.ExitWithError(err)
+			}
 		}
+
+		// wait until it becomes fully inactive (from draining status)
+		utils.Retry(func() (bool, error) {
+			service, err := c.awsClient.ECS().RetrieveService(ecsClusterName, ecsServiceName)
+			if err != nil {
+				return false, err
+			}
+			if service == nil || conv.S(service.Status) == "INACTIVE" {
+				return false, nil
+			}
+			return true, nil
+		}, time.Second, 5*time.Minute)
 	}
 
-	// wait until it becomes fully inactive (from draining status)
-	utils.Retry(func() (bool, error) {
-		service, err := c.awsClient.ECS().RetrieveService(ecsClusterName, conv.S(ecsServiceToDelete.ServiceName))
+	// delete IAM Role for ECS task execution
+	if !utils.IsBlank(ecsTaskExecutionRoleNameToDelete) {
+		console.RemovingResource("Deleting IAM Role", ecsTaskExecutionRoleNameToDelete, false)
+
+		if err := c.deleteIAMRole(ecsTaskExecutionRoleNameToDelete, []string{core.ECSTaskExecutionRolePolicyARN}); err != nil {
+			if conv.B(c.commandFlags.ContinueOnError) {
+				console.Error(err.Error())
+			} else {
+				return console.ExitWithError(err)
+			}
+		}
+	}
+
+	// delete IAM Role for app containers (with all of its attached policies)
+	if !utils.IsBlank(ecsTaskRoleNameToDelete) {
+		console.RemovingResource("Deleting IAM Role", ecsTaskRoleNameToDelete, false)
+
+		policyARNs, err := c.awsClient.IAM().ListAttachedRolePolicyARNs(ecsTaskRoleNameToDelete)
+		if err == nil {
+			err = c.deleteIAMRole(ecsTaskRoleNameToDelete, policyARNs)
+		} else {
+			err = fmt.Errorf("Failed to list policies of IAM Role [%s]: %s", ecsTaskRoleNameToDelete, err.Error())
+		}
 		if err != nil {
-			return false, err
+			if conv.B(c.commandFlags.ContinueOnError) {
+				console.Error(err.Error())
+			} else {
+				return console.ExitWithError(err)
+			}
 		}
-		if service == nil || conv.S(service.Status) == "INACTIVE" {
-			return false, nil
+	}
+
+	// delete IAM Role for scheduled tasks
+	if !utils.IsBlank(ecsEventsRoleNameToDelete) {
+		console.RemovingResource("Deleting IAM Role", ecsEventsRoleNameToDelete, false)
+
+		if err := c.deleteIAMRole(ecsEventsRoleNameToDelete, []string{core.ECSEventsRolePolicyARN}); err != nil {
+			if conv.B(c.commandFlags.ContinueOnError) {
+				console.Error(err.Error())
+			} else {
+				return console.ExitWithError(err)
+			}
 		}
-		return true, nil
-	}, time.Second, 5*time.Minute)
+	}
 
 	return nil
 }
@@ -261,21 +386,28 @@ func (c *Command) identifyECRResourcesToDelete(appName string, ecsService *ecs.S
 	return "", nil
 }
 
-func (c *Command) identifyELBResourcesToDelete(ecsService *ecs.Service) ([]*elbv2.LoadBalancer, []*elbv2.TargetGroup, []*_ec2.SecurityGroup, error) {
+func (c *Command) identifyELBResourcesToDelete(ecsServices []*ecs.Service) ([]*elbv2.LoadBalancer, []*elbv2.TargetGroup, []*_ec2.SecurityGroup, error) {
 	elbLoadBalancersToDelete := []*elbv2.LoadBalancer{}
 	elbTargetGroupsToDelete := []*elbv2.TargetGroup{}
 	elbLoadBalancerSecurityGroupsToDelete := []*_ec2.SecurityGroup{}
+	identified := make(map[string]bool) // ARNs and IDs of identified resources
 
-	for _, lb := range ecsService.LoadBalancers {
+	lbs := []*ecs.LoadBalancer{}
+	for _, ecsService := range ecsServices {
+		lbs = append(lbs, ecsService.LoadBalancers...)
+	}
+
+	for _, lb := range lbs {
 		elbTargetGroupARN := conv.S(lb.TargetGroupArn)
 		if !utils.IsBlank(elbTargetGroupARN) {
 			elbTargetGroup, err := c.awsClient.ELB().RetrieveTargetGroup(elbTargetGroupARN)
 			if err != nil {
 				return nil, nil, nil, console.ExitWithErrorString("Failed to retrieve ELB Target Group [%s]: %s", elbTargetGroupARN, err.Error())
 			}
-			if elbTargetGroup == nil {
+			if elbTargetGroup == nil || identified[elbTargetGroupARN] {
 				continue
 			}
+			identified[elbTargetGroupARN] = true
 
 			// check tags
 			tags, err := c.awsClient.ELB().RetrieveTags(conv.S(elbTargetGroup.TargetGroupArn))
@@ -295,9 +427,10 @@ func (c *Command) identifyELBResourcesToDelete(ecsService *ecs.Service) ([]*elbv
 					if err != nil {
 						return nil, nil, nil, console.ExitWithErrorString("Failed to retrieve ELB Load Balancer [%s]: %s", elbARN, err.Error())
 					}
-					if elbLoadBalancer == nil {
+					if elbLoadBalancer == nil || identified[conv.S(elbARN)] {
 						continue
 					}
+					identified[conv.S(elbARN)] = true
 
 					// check tags
 					tags, err := c.awsClient.ELB().RetrieveTags(conv.S(elbARN))
@@ -317,9 +450,10 @@ func (c *Command) identifyELBResourcesToDelete(ecsService *ecs.Service) ([]*elbv
 						if err != nil {
 							return nil, nil, nil, console.ExitWithErrorString("Failed to retrieve EC2 Security Group [%s]: %s", conv.S(securityGroupID), err.Error())
 						}
-						if elbLoadBalancerSecurityGroup == nil {
+						if elbLoadBalancerSecurityGroup == nil || identified[conv.S(securityGroupID)] {
 							continue
 						}
+						identified[conv.S(securityGroupID)] = true
 
 						// check tags
 						tags, err := c.awsClient.EC2().RetrieveTags(conv.S(elbLoadBalancerSecurityGroup.GroupId))
@@ -338,3 +472,72 @@ func (c *Command) identifyELBResourcesToDelete(ecsService *ecs.Service) ([]*elbv
 
 	return elbLoadBalancersToDelete, elbTargetGroupsToDelete, elbLoadBalancerSecurityGroupsToDelete, nil
 }
+
+// deleteELBListenerRules deletes the listener rules that forward to the ELB Target Group, except for the rules
+// of the ELB Load Balancers that will be deleted anyway.
+func (c *Command) deleteELBListenerRules(elbTargetGroup *elbv2.TargetGroup, elbLoadBalancersToDelete []*elbv2.LoadBalancer) error {
+	for _, elbARN := range elbTargetGroup.LoadBalancerArns {
+		deleted := false
+		for _, lb := range elbLoadBalancersToDelete {
+			if conv.S(lb.LoadBalancerArn) == conv.S(elbARN) {
+				deleted = true
+				break
+			}
+		}
+		if deleted {
+			continue
+		}
+
+		listeners, err := c.awsClient.ELB().RetrieveLoadBalancerListeners(conv.S(elbARN))
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve listeners for ELB Load Balancer [%s]: %s", conv.S(elbARN), err.Error())
+		}
+		for _, l := range listeners {
+			rules, err := c.awsClient.ELB().RetrieveListenerRules(conv.S(l.ListenerArn))
+			if err != nil {
+				return fmt.Errorf("Failed to retrieve rules of ELB Listener [%s]: %s", conv.S(l.ListenerArn), err.Error())
+			}
+			for _, r := range rules {
+				if conv.B(r.IsDefault) {
+					continue
+				}
+				for _, a := range r.Actions {
+					if conv.S(a.TargetGroupArn) == conv.S(elbTargetGroup.TargetGroupArn) {
+						console.RemovingResource("Deleting ELB Listener rule", conv.S(r.RuleArn), false)
+						if err := c.awsClient.ELB().DeleteRule(conv.S(r.RuleArn)); err != nil {
+							return fmt.Errorf("Failed to delete ELB Listener rule [%s]: %s", conv.S(r.RuleArn), err.Error())
+						}
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteIAMRole detaches the managed policies, deletes all inline policies and then deletes the IAM Role.
+func (c *Command) deleteIAMRole(roleName string, managedPolicyARNs []string) error {
+	for _, policyARN := range managedPolicyARNs {
+		if err := c.awsClient.IAM().DetachRolePolicy(policyARN, roleName); err != nil {
+			return fmt.Errorf("Failed to detach policy [%s] from IAM Role [%s]: %s", policyARN, roleName, err.Error())
+		}
+	}
+
+	policyNames, err := c.awsClient.IAM().ListRolePolicyNames(roleName)
+	if err != nil {
+		return fmt.Errorf("Failed to list policies of IAM Role [%s]: %s", roleName, err.Error())
+	}
+	for _, policyName := range policyNames {
+		if err := c.awsClient.IAM().DeleteRolePolicy(policyName, roleName); err != nil {
+			return fmt.Errorf("Failed to delete policy [%s] from IAM Role [%s]: %s", policyName, roleName, err.Error())
+		}
+	}
+
+	if err := c.awsClient.IAM().DeleteRole(roleName); err != nil {
+		return fmt.Errorf("Failed to delete IAM Role [%s]: %s", roleName, err.Error())
+	}
+
+	return nil
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"math"
 
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/coldbrewcloud/coldbrew-cli/aws"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
+	"github.com/coldbrewcloud/coldbrew-cli/config"
 	"github.com/coldbrewcloud/coldbrew-cli/console"
 	"github.com/coldbrewcloud/coldbrew-cli/core"
 	"github.com/coldbrewcloud/coldbrew-cli/utils"
@@ -14,25 +17,7 @@ import (
 )
 
 func (c *Command) updateECSTaskDefinition(dockerImageFullURI string) (string, error) {
-	// port mappings
-	var portMappings []ecs.PortMapping
-	if conv.U16(c.conf.Port) > 0 {
-		portMappings = []ecs.PortMapping{
-			{
-				ContainerPort: conv.U16(c.conf.Port),
-				Protocol:      "tcp",
-			},
-		}
-	}
-
 	ecsTaskDefinitionName := core.DefaultECSTaskDefinitionName(conv.S(c.conf.Name))
-	ecsTaskContainerName := core.DefaultECSTaskMainContainerName(conv.S(c.conf.Name))
-	cpu := uint64(math.Ceil(conv.F64(c.conf.CPU) * 1024.0))
-	memory, err := core.ParseSizeExpression(conv.S(c.conf.Memory))
-	if err != nil {
-		return "", err
-	}
-	memory /= 1000 * 1000
 
 	// logging
 	loggingDriver := conv.S(c.conf.Logging.Driver)
@@ -56,18 +41,39 @@ func (c *Command) updateECSTaskDefinition(dockerImageFullURI string) (string, er
 		if !ok || utils.IsBlank(awsLogsRegionName) {
 			c.conf.Logging.Options["awslogs-region"] = conv.S(c.globalFlags.AWSRegion)
 		}
+
+		// stream prefix: log streams are named after the ECS Tasks
+		awsLogsStreamPrefix, ok := c.conf.Logging.Options["awslogs-stream-prefix"]
+		if !ok || utils.IsBlank(awsLogsStreamPrefix) {
+			c.conf.Logging.Options["awslogs-stream-prefix"] = core.DefaultCloudWatchLogsStreamPrefix()
+		}
+	}
+
+	containers, err := c.ecsContainerDefinitions(dockerImageFullURI)
+	if err != nil {
+		return "", err
+	}
+
+	// execution role to read secrets
+	executionRoleARN, err := c.prepareECSTaskExecutionRole()
+	if err != nil {
+		return "", err
+	}
+
+	// task role for the containers
+	taskRoleARN, err := c.prepareECSTaskRole()
+	if err != nil {
+		return "", err
 	}
 
 	console.UpdatingResource("Updating ECS Task Definition", ecsTaskDefinitionName, false)
-	ecsTaskDef, err := c.awsClient.ECS().UpdateTaskDefinition(
-		ecsTaskDefinitionName,
-		dockerImageFullURI,
-		ecsTaskContainerName,
-		cpu,
-		memory,
-		c.conf.Env,
-		portMappings,
-		loggingDriver, c.conf.Logging.Options)
+	ecsTaskDef, err := c.awsClient.ECS().UpdateTaskDefinition(&ecs.TaskDefinitionInput{
+		Family:           ecsTaskDefinitionName,
+		ExecutionRoleARN: executionRoleARN,
+		TaskRoleARN:      taskRoleARN,
+		Containers:       containers,
+		Volumes:          c.ecsVolumes(),
+	})
 	if err != nil {
 		return "", fmt.Errorf("Failed to update ECS Task Definition [%s]: %s", ecsTaskDefinitionName, err.Error())
 	}
@@ -75,15 +81,230 @@ func (c *Command) updateECSTaskDefinition(dockerImageFullURI string) (string, er
 	return conv.S(ecsTaskDef.TaskDefinitionArn), nil
 }
 
-func (c *Command) createOrUpdateECSService(ecsTaskDefinitionARN string) error {
+// ecsContainerDefinitions returns the app container followed by the additional containers
+// in the configuration. All containers share the app's logging configuration.
+func (c *Command) ecsContainerDefinitions(dockerImageFullURI string) ([]*ecs.ContainerDefinition, error) {
+	// port mappings
+	var portMappings []ecs.PortMapping
+	for _, port := range c.conf.AppPorts() {
+		portMappings = append(portMappings, ecs.PortMapping{
+			ContainerPort: conv.U16(port.ContainerPort),
+			HostPort:      conv.U16(port.HostPort),
+			Protocol:      conv.S(port.Protocol),
+		})
+	}
+
+	memory, err := core.ParseSizeExpression(conv.S(c.conf.Memory))
+	if err != nil {
+		return nil, err
+	}
+	memoryReservation, err := parseMemoryReservation(c.conf.MemoryReservation)
+	if err != nil {
+		return nil, err
+	}
+
+	healthCheck, err := ecsHealthCheck(&c.conf.HealthCheck)
+	if err != nil {
+		return nil, err
+	}
+
+	loggingDriver := conv.S(c.conf.Logging.Driver)
+	envs, secrets := splitEnvs(c.conf.Env)
+	containers := []*ecs.ContainerDefinition{
+		{
+			Name:              core.DefaultECSTaskMainContainerName(conv.S(c.conf.Name)),
+			Image:             dockerImageFullURI,
+			CPU:               uint64(math.Ceil(conv.F64(c.conf.CPU) * 1024.0)),
+			Memory:            memory / (1000 * 1000),
+			MemoryReservation: memoryReservation / (1000 * 1000),
+			Essential:         true,
+			Envs:              envs,
+			Secrets:           secrets,
+			PortMappings:      portMappings,
+			MountPoints:       ecsMountPoints(c.conf.Mounts),
+			LogDriver:         loggingDriver,
+			LogDriverOptions:  c.conf.Logging.Options,
+			HealthCheck:       healthCheck,
+		},
+	}
+	if err := setRuntimeOptions(containers[0], &c.conf.Runtime); err != nil {
+		return nil, err
+	}
+
+	for _, container := range c.conf.Containers {
+		memory, err := core.ParseSizeExpression(conv.S(container.Memory))
+		if err != nil {
+			return nil, err
+		}
+		memoryReservation, err := parseMemoryReservation(container.MemoryReservation)
+		if err != nil {
+			return nil, err
+		}
+
+		var portMappings []ecs.PortMapping
+		for _, port := range container.Ports {
+			portMappings = append(portMappings, ecs.PortMapping{
+				ContainerPort: conv.U16(port.ContainerPort),
+				HostPort:      conv.U16(port.HostPort),
+				Protocol:      conv.S(port.Protocol),
+			})
+		}
+
+		var dependsOn []ecs.ContainerDependency
+		for _, d := range container.DependsOn {
+			dependsOn = append(dependsOn, ecs.ContainerDependency{
+				ContainerName: conv.S(d.Container),
+				Condition:     conv.S(d.Condition),
+			})
+		}
+
+		healthCheck, err := ecsHealthCheck(&container.HealthCheck)
+		if err != nil {
+			return nil, err
+		}
+
+		envs, secrets := splitEnvs(container.Env)
+		containerDefinition := &ecs.ContainerDefinition{
+			Name:              conv.S(container.Name),
+			Image:             conv.S(container.Image),
+			CPU:               uint64(math.Ceil(conv.F64(container.CPU) * 1024.0)),
+			Memory:            memory / (1000 * 1000),
+			MemoryReservation: memoryReservation / (1000 * 1000),
+			Essential:         conv.B(container.Essential),
+			Envs:              envs,
+			Secrets:           secrets,
+			PortMappings:      portMappings,
+			DependsOn:         dependsOn,
+			Links:             container.Links,
+			MountPoints:       ecsMountPoints(container.Mounts),
+			LogDriver:         loggingDriver,
+			LogDriverOptions:  c.conf.Logging.Options,
+			HealthCheck:       healthCheck,
+		}
+		if err := setRuntimeOptions(containerDefinition, &container.Runtime); err != nil {
+			return nil, err
+		}
+		containers = append(containers, containerDefinition)
+	}
+
+	return containers, nil
+}
+
+func (c *Command) ecsVolumes() []*ecs.Volume {
+	var volumes []*ecs.Volume
+	for _, v := range c.conf.Volumes {
+		volume := &ecs.Volume{Name: conv.S(v.Name)}
+		switch conv.S(v.Type) {
+		case aws.ECSVolumeTypeHost:
+			volume.HostPath = conv.S(v.HostPath)
+		case aws.ECSVolumeTypeDocker:
+			volume.Docker = &ecs.DockerVolume{
+				Driver:        conv.S(v.Docker.Driver),
+				DriverOptions: v.Docker.DriverOptions,
+				Scope:         conv.S(v.Docker.Scope),
+				Autoprovision: conv.B(v.Docker.Autoprovision),
+			}
+		case aws.ECSVolumeTypeEFS:
+			volume.EFS = &ecs.EFSVolume{
+				FileSystemID:      conv.S(v.EFS.FileSystemID),
+				RootDirectory:     conv.S(v.EFS.RootDirectory),
+				AccessPointID:     conv.S(v.EFS.AccessPointID),
+				TransitEncryption: conv.B(v.EFS.TransitEncryption),
+				IAM:               conv.B(v.EFS.IAM),
+			}
+		}
+		volumes = append(volumes, volume)
+	}
+	return volumes
+}
+
+func ecsMountPoints(mounts []*config.ConfigMount) []ecs.MountPoint {
+	var mountPoints []ecs.MountPoint
+	for _, m := range mounts {
+		mountPoints = append(mountPoints, ecs.MountPoint{
+			SourceVolume:  conv.S(m.Volume),
+			ContainerPath: conv.S(m.Path),
+			ReadOnly:      conv.B(m.ReadOnly),
+		})
+	}
+	return mountPoints
+}
+
+// parseMemoryReservation returns 0 if the memory reservation is not set.
+func parseMemoryReservation(memoryReservation *string) (uint64, error) {
+	if memoryReservation == nil {
+		return 0, nil
+	}
+	return core.ParseSizeExpression(conv.S(memoryReservation))
+}
+
+func setRuntimeOptions(containerDefinition *ecs.ContainerDefinition, runtime *config.ConfigRuntime) error {
+	containerDefinition.Command = runtime.Command
+	containerDefinition.EntryPoint = runtime.EntryPoint
+	containerDefinition.WorkingDirectory = conv.S(runtime.WorkingDirectory)
+	containerDefinition.User = conv.S(runtime.User)
+	for _, u := range runtime.Ulimits {
+		containerDefinition.Ulimits = append(containerDefinition.Ulimits, ecs.Ulimit{
+			Name:      conv.S(u.Name),
+			SoftLimit: conv.U64(u.Soft),
+			HardLimit: conv.U64(u.Hard),
+		})
+	}
+	containerDefinition.DockerLabels = runtime.Labels
+	containerDefinition.ExtraHosts = runtime.ExtraHosts
+	containerDefinition.DNSServers = runtime.DNSServers
+
+	if stopTimeout := conv.S(runtime.StopTimeout); stopTimeout != "" {
+		seconds, err := core.ParseTimeExpression(stopTimeout)
+		if err != nil {
+			return err
+		}
+		containerDefinition.StopTimeout = seconds
+	}
+
+	return nil
+}
+
+// ecsHealthCheck returns nil if the health check has no command.
+func ecsHealthCheck(healthCheck *config.ConfigHealthCheck) (*ecs.HealthCheck, error) {
+	if len(healthCheck.Command) == 0 {
+		return nil, nil
+	}
+
+	interval, err := core.ParseTimeExpression(conv.S(healthCheck.Interval))
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := core.ParseTimeExpression(conv.S(healthCheck.Timeout))
+	if err != nil {
+		return nil, err
+	}
+	startPeriod, err := core.ParseTimeExpression(conv.S(healthCheck.StartPeriod))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ecs.HealthCheck{
+		Command:     healthCheck.Command,
+		Interval:    interval,
+		Timeout:     timeout,
+		Retries:     conv.U16(healthCheck.Retries),
+		StartPeriod: startPeriod,
+	}, nil
+}
+
+// createOrUpdateECSService returns the ARN of the ECS Task Definition the service was running
+// before the update. It returns an empty string if a new ECS Service was created.
+func (c *Command) createOrUpdateECSService(ecsTaskDefinitionARN string) (string, error) {
 	ecsClusterName := core.DefaultECSClusterName(conv.S(c.conf.ClusterName))
 	ecsServiceName := core.DefaultECSServiceName(conv.S(c.conf.Name))
 
 	ecsService, err := c.awsClient.ECS().RetrieveService(ecsClusterName, ecsServiceName)
 	if err != nil {
-		return fmt.Errorf("Failed to retrieve ECS Service [%s/%s]: %s", ecsClusterName, ecsServiceName, err.Error())
+		return "", fmt.Errorf("Failed to retrieve ECS Service [%s/%s]: %s", ecsClusterName, ecsServiceName, err.Error())
 	}
 
+	previousTaskDefinitionARN := ""
 	if ecsService != nil && conv.S(ecsService.Status) == "ACTIVE" {
 		elbLoadBalancerName := ""
 		elbTargetGroupARN := ""
@@ -92,33 +313,39 @@ func (c *Command) createOrUpdateECSService(ecsTaskDefinitionARN string) error {
 			elbTargetGroupARN = conv.S(ecsService.LoadBalancers[0].TargetGroupArn)
 
 			// check if task container port has changed or not
-			if conv.I64(ecsService.LoadBalancers[0].ContainerPort) != int64(conv.U16(c.conf.Port)) {
-				return core.NewErrorExtraInfo(
-					errors.New("App port cannot be changed."),
+			if conv.I64(ecsService.LoadBalancers[0].ContainerPort) != int64(c.conf.LoadBalancedPort()) {
+				return "", core.NewErrorExtraInfo(
+					errors.New("Load balanced app port cannot be changed."),
 					"https://github.com/coldbrewcloud/coldbrew-cli/wiki/Configuration-Changes-and-Their-Effects#app-level-changes")
 			}
 		}
 
+		previousTaskDefinitionARN = conv.S(ecsService.TaskDefinition)
+
 		if err := c.updateECSService(ecsClusterName, ecsServiceName, ecsTaskDefinitionARN, elbLoadBalancerName, elbTargetGroupARN); err != nil {
-			return err
+			return "", err
 		}
 	} else {
 		if err := c.createECSService(ecsClusterName, ecsServiceName, ecsTaskDefinitionARN); err != nil {
-			return err
+			return "", err
 		}
 	}
 
-	return nil
+	if err := commands.UpdateAutoScaling(c.awsClient, c.conf, ecsClusterName, ecsServiceName); err != nil {
+		return "", err
+	}
+
+	return previousTaskDefinitionARN, nil
 }
 
 func (c *Command) createECSService(ecsClusterName, ecsServiceName, ecsTaskDefinitionARN string) error {
 	ecsServiceRoleName := core.DefaultECSServiceRoleName(conv.S(c.conf.ClusterName))
 	ecsTaskContainerName := conv.S(c.conf.Name)
-	ecsTaskContainerPort := conv.U16(c.conf.Port)
+	ecsTaskContainerPort := c.conf.LoadBalancedPort()
 
 	var loadBalancers []*ecs.LoadBalancer
 	if conv.B(c.conf.LoadBalancer.Enabled) {
-		if conv.U16(c.conf.Port) == 0 {
+		if ecsTaskContainerPort == 0 {
 			return errors.New("App port must be specified to enable load balancer.")
 		}
 
@@ -135,8 +362,8 @@ func (c *Command) createECSService(ecsClusterName, ecsServiceName, ecsTaskDefini
 
 	console.AddingResource("Creating ECS Service", ecsServiceName, false)
 	_, err := c.awsClient.ECS().CreateService(
-		ecsClusterName, ecsServiceName, ecsTaskDefinitionARN, conv.U16(c.conf.Units),
-		loadBalancers, ecsServiceRoleName)
+		ecsClusterName, ecsServiceName, ecsTaskDefinitionARN, c.ecsInitialDesiredCount(nil),
+		loadBalancers, ecsServiceRoleName, c.ecsDeploymentConfiguration(), c.ecsPlacement())
 	if err != nil {
 		return fmt.Errorf("Failed to create ECS Service [%s]: %s", ecsServiceName, err.Error())
 	}
@@ -154,7 +381,7 @@ func (c *Command) updateECSService(ecsClusterName, ecsServiceName, ecsTaskDefini
 
 	// update ECS service
 	console.UpdatingResource("Updating ECS Service", ecsServiceName, false)
-	_, err := c.awsClient.ECS().UpdateService(ecsClusterName, ecsServiceName, ecsTaskDefinitionARN, conv.U16(c.conf.Units))
+	_, err := c.awsClient.ECS().UpdateService(ecsClusterName, ecsServiceName, ecsTaskDefinitionARN, c.ecsDesiredCount(), c.ecsDeploymentConfiguration(), c.ecsPlacement())
 	if err != nil {
 		return fmt.Errorf("Failed to update ECS Service [%s]: %s", ecsServiceName, err.Error())
 	}
@@ -162,6 +389,61 @@ func (c *Command) updateECSService(ecsClusterName, ecsServiceName, ecsTaskDefini
 	return nil
 }
 
+func (c *Command) ecsPlacement() *ecs.Placement {
+	placement := &ecs.Placement{}
+	for _, s := range c.conf.Placement.Strategy {
+		placement.Strategies = append(placement.Strategies, ecs.PlacementStrategy{
+			Type:  conv.S(s.Type),
+			Field: conv.S(s.Field),
+		})
+	}
+	for _, pc := range c.conf.Placement.Constraints {
+		placement.Constraints = append(placement.Constraints, ecs.PlacementConstraint{
+			Type:       conv.S(pc.Type),
+			Expression: conv.S(pc.Expression),
+		})
+	}
+	return placement
+}
+
+// ecsDesiredCount returns nil if auto scaling is enabled so that updating the ECS Service does not overwrite
+// the desired count managed by Application Auto Scaling.
+func (c *Command) ecsDesiredCount() *uint16 {
+	if conv.B(c.conf.AutoScaling.Enabled) {
+		return nil
+	}
+	return conv.U16P(conv.U16(c.conf.Units))
+}
+
+// ecsInitialDesiredCount returns the desired count of an ECS Service that starts with no tasks. With auto scaling
+// enabled, it's the desired count of runningService (or units if nil) bounded by the min and max units.
+func (c *Command) ecsInitialDesiredCount(runningService *_ecs.Service) uint16 {
+	if !conv.B(c.conf.AutoScaling.Enabled) {
+		return conv.U16(c.conf.Units)
+	}
+
+	desiredCount := conv.U16(c.conf.Units)
+	if runningService != nil {
+		desiredCount = uint16(conv.I64(runningService.DesiredCount))
+	}
+	if minUnits := conv.U16(c.conf.AutoScaling.MinUnits); desiredCount < minUnits {
+		desiredCount = minUnits
+	}
+	if maxUnits := conv.U16(c.conf.AutoScaling.MaxUnits); desiredCount > maxUnits {
+		desiredCount = maxUnits
+	}
+	return desiredCount
+}
+
+func (c *Command) ecsDeploymentConfiguration() *ecs.DeploymentConfiguration {
+	return &ecs.DeploymentConfiguration{
+		MaxPercent:             conv.U16(c.conf.Deployment.MaxPercent),
+		MinHealthyPercent:      conv.U16(c.conf.Deployment.MinHealthyPercent),
+		CircuitBreaker:         conv.B(c.conf.Deployment.CircuitBreaker.Enabled),
+		CircuitBreakerRollback: conv.B(c.conf.Deployment.CircuitBreaker.Rollback),
+	}
+}
+
 func (c *Command) PrepareCloudWatchLogsGroup(groupName string) error {
 	groups, err := c.awsClient.CloudWatchLogs().ListGroups(groupName)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	_elb "github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/ec2"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/ecs"
 	"github.com/coldbrewcloud/coldbrew-cli/aws/elb"
@@ -317,17 +318,7 @@ func (c *Command) checkLoadBalancerHealthCheckChanges(elbTargetGroupARN string)
 		return nil
 	}
 
-	currentStatusMatcher := ""
-	if elbTargetGroup.Matcher != nil {
-		currentStatusMatcher = conv.S(elbTargetGroup.Matcher.HttpCode)
-	}
-
-	if conv.I64(elbTargetGroup.HealthCheckIntervalSeconds) != int64(checkInterval) ||
-		conv.I64(elbTargetGroup.HealthCheckTimeoutSeconds) != int64(timeout) ||
-		conv.S(elbTargetGroup.HealthCheckPath) != conv.S(c.conf.LoadBalancer.HealthCheck.Path) ||
-		conv.I64(elbTargetGroup.HealthyThresholdCount) != int64(conv.U16(c.conf.LoadBalancer.HealthCheck.HealthyLimit)) ||
-		conv.I64(elbTargetGroup.UnhealthyThresholdCount) != int64(conv.U16(c.conf.LoadBalancer.HealthCheck.UnhealthyLimit)) ||
-		currentStatusMatcher != conv.S(c.conf.LoadBalancer.HealthCheck.Status) {
+	if len(c.healthCheckChanges(elbTargetGroup, checkInterval, timeout)) > 0 {
 		// need to update Target Group health check settings
 
 		healthCheckParams := &elb.HealthCheckParams{
@@ -348,3 +339,27 @@ func (c *Command) checkLoadBalancerHealthCheckChanges(elbTargetGroupARN string)
 
 	return nil
 }
+
+// healthCheckChanges returns the differences between the health check settings of the ELB Target Group
+// and the app configuration.
+func (c *Command) healthCheckChanges(elbTargetGroup *_elb.TargetGroup, checkInterval, timeout uint64) []configChange {
+	currentStatusMatcher := ""
+	if elbTargetGroup.Matcher != nil {
+		currentStatusMatcher = conv.S(elbTargetGroup.Matcher.HttpCode)
+	}
+
+	changes := []configChange{}
+	changes = appendChange(changes, "Interval",
+		fmt.Sprintf("%ds", conv.I64(elbTargetGroup.HealthCheckIntervalSeconds)), fmt.Sprintf("%ds", checkInterval))
+	changes = appendChange(changes, "Timeout",
+		fmt.Sprintf("%ds", conv.I64(elbTargetGroup.HealthCheckTimeoutSeconds)), fmt.Sprintf("%ds", timeout))
+	changes = appendChange(changes, "Path",
+		conv.S(elbTargetGroup.HealthCheckPath), conv.S(c.conf.LoadBalancer.HealthCheck.Path))
+	changes = appendChange(changes, "Healthy Limit",
+		fmt.Sprintf("%d", conv.I64(elbTargetGroup.HealthyThresholdCount)), fmt.Sprintf("%d", conv.U16(c.conf.LoadBalancer.HealthCheck.HealthyLimit)))
+	changes = appendChange(changes, "Unhealthy Limit",
+		fmt.Sprintf("%d", conv.I64(elbTargetGroup.UnhealthyThresholdCount)), fmt.Sprintf("%d", conv.U16(c.conf.LoadBalancer.HealthCheck.UnhealthyLimit)))
+	changes = appendChange(changes, "Status", currentStatusMatcher, conv.S(c.conf.LoadBalancer.HealthCheck.Status))
+
+	return changes
+}
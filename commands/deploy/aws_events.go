@@ -0,0 +1,195 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/coldbrewcloud/coldbrew-cli/aws/events"
+	"github.com/coldbrewcloud/coldbrew-cli/config"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+// scheduleTargetID is the target ID of the ECS task target in the CloudWatch Events Rule of each schedule.
+const scheduleTargetID = "app"
+
+// updateSchedules creates or updates the CloudWatch Events Rules of the schedules in the configuration so that they
+// run the ECS Task Definition, and deletes the rules of the schedules that were removed from the configuration.
+func (c *Command) updateSchedules(ecsTaskDefinitionARN string) error {
+	clusterName := conv.S(c.conf.ClusterName)
+	appName := conv.S(c.conf.Name)
+
+	rulePrefix := core.DefaultCloudWatchEventsRuleNamePrefix(clusterName, appName)
+	currentRuleNames, err := c.awsClient.CloudWatchEvents().ListRuleNames(rulePrefix)
+	if err != nil {
+		return fmt.Errorf("Failed to list CloudWatch Events Rules [%s*]: %s", rulePrefix, err.Error())
+	}
+
+	configuredRuleNames := make(map[string]bool)
+	if len(c.conf.Schedules) > 0 {
+		ecsClusterName := core.DefaultECSClusterName(clusterName)
+		ecsCluster, err := c.awsClient.ECS().RetrieveCluster(ecsClusterName)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve ECS Cluster [%s]: %s", ecsClusterName, err.Error())
+		}
+		if ecsCluster == nil {
+			return fmt.Errorf("ECS Cluster [%s] not found", ecsClusterName)
+		}
+
+		roleARN, err := c.prepareECSEventsRole()
+		if err != nil {
+			return err
+		}
+
+		for _, schedule := range c.conf.Schedules {
+			ruleName := core.DefaultCloudWatchEventsRuleName(clusterName, appName, conv.S(schedule.Name))
+			configuredRuleNames[ruleName] = true
+
+			console.UpdatingResource("Updating CloudWatch Events Rule", ruleName, false)
+			description := fmt.Sprintf("coldbrew schedule [%s] of app [%s]", conv.S(schedule.Name), appName)
+			if err := c.awsClient.CloudWatchEvents().PutScheduleRule(ruleName, conv.S(schedule.Expression), description); err != nil {
+				return fmt.Errorf("Failed to update CloudWatch Events Rule [%s]: %s", ruleName, err.Error())
+			}
+			target := &events.ECSTaskTarget{
+				ClusterARN:        conv.S(ecsCluster.ClusterArn),
+				TaskDefinitionARN: ecsTaskDefinitionARN,
+				RoleARN:           roleARN,
+				TaskCount:         conv.U16(schedule.Count),
+				ContainerName:     core.DefaultECSTaskMainContainerName(appName),
+				Command:           schedule.Command,
+			}
+			if err := c.awsClient.CloudWatchEvents().PutECSTaskTarget(ruleName, scheduleTargetID, target); err != nil {
+				return fmt.Errorf("Failed to update target of CloudWatch Events Rule [%s]: %s", ruleName, err.Error())
+			}
+		}
+	}
+
+	for _, ruleName := range currentRuleNames {
+		if !configuredRuleNames[ruleName] {
+			console.RemovingResource("Deleting CloudWatch Events Rule", ruleName, false)
+			if err := c.awsClient.CloudWatchEvents().DeleteRule(ruleName); err != nil {
+				return fmt.Errorf("Failed to delete CloudWatch Events Rule [%s]: %s", ruleName, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// prepareECSEventsRole creates (if needed) the IAM Role that CloudWatch Events runs the scheduled tasks with,
+// and returns its ARN.
+func (c *Command) prepareECSEventsRole() (string, error) {
+	roleName := core.DefaultECSEventsRoleName(conv.S(c.conf.ClusterName), conv.S(c.conf.Name))
+	role, err := c.awsClient.IAM().RetrieveRole(roleName)
+	if err != nil {
+		return "", fmt.Errorf("Failed to retrieve IAM Role [%s]: %s", roleName, err.Error())
+	}
+	if role == nil {
+		console.AddingResource("Creating IAM Role", roleName, false)
+		role, err = c.awsClient.IAM().CreateRole(core.EventsAssumeRolePolicy, roleName)
+		if err != nil {
+			return "", fmt.Errorf("Failed to create IAM Role [%s]: %s", roleName, err.Error())
+		}
+		if err := c.awsClient.IAM().AttachRolePolicy(core.ECSEventsRolePolicyARN, roleName); err != nil {
+			return "", fmt.Errorf("Failed to attach policy to IAM Role [%s]: %s", roleName, err.Error())
+		}
+	}
+
+	return conv.S(role.Arn), nil
+}
+
+// planSchedules prints the changes that updateSchedules would make.
+func (c *Command) planSchedules() error {
+	clusterName := conv.S(c.conf.ClusterName)
+	appName := conv.S(c.conf.Name)
+
+	rulePrefix := core.DefaultCloudWatchEventsRuleNamePrefix(clusterName, appName)
+	currentRuleNames, err := c.awsClient.CloudWatchEvents().ListRuleNames(rulePrefix)
+	if err != nil {
+		return fmt.Errorf("Failed to list CloudWatch Events Rules [%s*]: %s", rulePrefix, err.Error())
+	}
+	current := make(map[string]bool)
+	for _, ruleName := range currentRuleNames {
+		current[ruleName] = true
+	}
+
+	configured := make(map[string]bool)
+	for _, schedule := range c.conf.Schedules {
+		ruleName := core.DefaultCloudWatchEventsRuleName(clusterName, appName, conv.S(schedule.Name))
+		configured[ruleName] = true
+
+		if !current[ruleName] {
+			console.PlanAddResource("CloudWatch Events Rule", ruleName)
+			printChanges(scheduleChanges("", nil, nil, schedule))
+			continue
+		}
+
+		rule, err := c.awsClient.CloudWatchEvents().RetrieveRule(ruleName)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve CloudWatch Events Rule [%s]: %s", ruleName, err.Error())
+		}
+		currentExpression := ""
+		if rule != nil {
+			currentExpression = conv.S(rule.ScheduleExpression)
+		}
+		targets, err := c.awsClient.CloudWatchEvents().ListTargets(ruleName)
+		if err != nil {
+			return fmt.Errorf("Failed to list targets of CloudWatch Events Rule [%s]: %s", ruleName, err.Error())
+		}
+		var currentCount *int64
+		var currentCommand []string
+		for _, target := range targets {
+			if conv.S(target.Id) == scheduleTargetID && target.EcsParameters != nil {
+				currentCount = target.EcsParameters.TaskCount
+				currentCommand = targetCommand(conv.S(target.Input), core.DefaultECSTaskMainContainerName(appName))
+			}
+		}
+
+		// the target always runs the new ECS Task Definition
+		console.PlanUpdateResource("CloudWatch Events Rule", ruleName)
+		printChanges(scheduleChanges(currentExpression, currentCount, currentCommand, schedule))
+	}
+
+	for _, ruleName := range currentRuleNames {
+		if !configured[ruleName] {
+			console.PlanRemoveResource("CloudWatch Events Rule", ruleName)
+		}
+	}
+
+	return nil
+}
+
+// scheduleChanges returns the changes of the schedule's CloudWatch Events Rule and its ECS task target.
+// Current count is nil if the rule has no target.
+func scheduleChanges(currentExpression string, currentCount *int64, currentCommand []string, schedule *config.ConfigSchedule) []configChange {
+	currentCountString := ""
+	if currentCount != nil {
+		currentCountString = conv.I64S(*currentCount)
+	}
+
+	changes := []configChange{}
+	changes = appendChange(changes, "Expression", currentExpression, conv.S(schedule.Expression))
+	changes = appendChange(changes, "Task Count", currentCountString, conv.I64S(int64(conv.U16(schedule.Count))))
+	changes = appendChange(changes, "Command", commandString(currentCommand), commandString(schedule.Command))
+	return changes
+}
+
+// targetCommand returns the command override of the container in the target input.
+func targetCommand(input, containerName string) []string {
+	var overrides struct {
+		ContainerOverrides []struct {
+			Name    string   `json:"name"`
+			Command []string `json:"command"`
+		} `json:"containerOverrides"`
+	}
+	if err := json.Unmarshal([]byte(input), &overrides); err != nil {
+		return nil
+	}
+	for _, o := range overrides.ContainerOverrides {
+		if o.Name == containerName {
+			return o.Command
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+type iamPolicyDocument struct {
+	Version   string               `json:"Version"`
+	Statement []iamPolicyStatement `json:"Statement"`
+}
+
+type iamPolicyStatement struct {
+	Effect   string   `json:"Effect"`
+	Action   []string `json:"Action"`
+	Resource []string `json:"Resource"`
+}
+
+// splitEnvs separates secret references from plain environment variables.
+// Secrets are returned as env name -> reference ("valueFrom" of ECS container secrets).
+func splitEnvs(envs map[string]string) (map[string]string, map[string]string) {
+	plain := make(map[string]string)
+	secrets := make(map[string]string)
+	for k, v := range envs {
+		if _, reference, ok := core.ParseEnvSecret(v); ok {
+			secrets[k] = reference
+		} else {
+			plain[k] = v
+		}
+	}
+	return plain, secrets
+}
+
+// secretResourceARNs returns the ARNs of all secrets referenced by the app and additional containers
+// grouped by their source.
+func (c *Command) secretResourceARNs() map[string][]string {
+	allEnvs := []map[string]string{c.conf.Env}
+	for _, container := range c.conf.Containers {
+		allEnvs = append(allEnvs, container.Env)
+	}
+
+	region := conv.S(c.globalFlags.AWSRegion)
+	unique := make(map[string]map[string]bool)
+	for _, envs := range allEnvs {
+		for _, v := range envs {
+			if source, reference, ok := core.ParseEnvSecret(v); ok {
+				if unique[source] == nil {
+					unique[source] = make(map[string]bool)
+				}
+				unique[source][core.SecretResourceARN(source, reference, region)] = true
+			}
+		}
+	}
+
+	resources := make(map[string][]string)
+	for source, arns := range unique {
+		for arn := range arns {
+			resources[source] = append(resources[source], arn)
+		}
+		sort.Strings(resources[source])
+	}
+	return resources
+}
+
+// prepareECSTaskExecutionRole creates (if needed) the IAM Role that ECS uses to read the secrets
+// referenced in the app configuration, and returns its ARN. It returns an empty string if no
+// secrets are referenced.
+func (c *Command) prepareECSTaskExecutionRole() (string, error) {
+	resources := c.secretResourceARNs()
+	if len(resources) == 0 {
+		return "", nil
+	}
+
+	roleName := core.DefaultECSTaskExecutionRoleName(conv.S(c.conf.ClusterName), conv.S(c.conf.Name))
+	role, err := c.awsClient.IAM().RetrieveRole(roleName)
+	if err != nil {
+		return "", fmt.Errorf("Failed to retrieve IAM Role [%s]: %s", roleName, err.Error())
+	}
+	if role == nil {
+		console.AddingResource("Creating IAM Role", roleName, false)
+		role, err = c.awsClient.IAM().CreateRole(core.ECSTasksAssumeRolePolicy, roleName)
+		if err != nil {
+			return "", fmt.Errorf("Failed to create IAM Role [%s]: %s", roleName, err.Error())
+		}
+		if err := c.awsClient.IAM().AttachRolePolicy(core.ECSTaskExecutionRolePolicyARN, roleName); err != nil {
+			return "", fmt.Errorf("Failed to attach policy to IAM Role [%s]: %s", roleName, err.Error())
+		}
+	}
+
+	policy := iamPolicyDocument{Version: "2012-10-17"}
+	if arns := resources[core.EnvSecretSourceSSM]; len(arns) > 0 {
+		policy.Statement = append(policy.Statement, iamPolicyStatement{
+			Effect:   "Allow",
+			Action:   []string{"ssm:GetParameters"},
+			Resource: arns,
+		})
+	}
+	if arns := resources[core.EnvSecretSourceSecretsManager]; len(arns) > 0 {
+		policy.Statement = append(policy.Statement, iamPolicyStatement{
+			Effect:   "Allow",
+			Action:   []string{"secretsmanager:GetSecretValue"},
+			Resource: arns,
+		})
+	}
+	policyDocument, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+
+	console.UpdatingResource("Granting access to secrets for IAM Role", roleName, false)
+	if err := c.awsClient.IAM().PutRolePolicy(core.DefaultECSTaskExecutionSecretsPolicyName(), string(policyDocument), roleName); err != nil {
+		return "", fmt.Errorf("Failed to update policy of IAM Role [%s]: %s", roleName, err.Error())
+	}
+
+	return conv.S(role.Arn), nil
+}
+
+// ecsTaskRolePolicyDocument returns the inline policy document of the task role, or an empty string if the
+// configuration has no policy statements.
+func (c *Command) ecsTaskRolePolicyDocument() (string, error) {
+	if len(c.conf.IAM.Statements) == 0 {
+		return "", nil
+	}
+
+	policy := iamPolicyDocument{Version: "2012-10-17"}
+	for _, s := range c.conf.IAM.Statements {
+		policy.Statement = append(policy.Statement, iamPolicyStatement{
+			Effect:   conv.S(s.Effect),
+			Action:   s.Actions,
+			Resource: s.Resources,
+		})
+	}
+	policyDocument, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+	return string(policyDocument), nil
+}
+
+// prepareECSTaskRole creates (if needed) the IAM Role that the app's containers run with, updates its
+// managed policies and inline policy to match the configuration, and returns its ARN.
+func (c *Command) prepareECSTaskRole() (string, error) {
+	roleName := core.DefaultECSTaskRoleName(conv.S(c.conf.ClusterName), conv.S(c.conf.Name))
+	role, err := c.awsClient.IAM().RetrieveRole(roleName)
+	if err != nil {
+		return "", fmt.Errorf("Failed to retrieve IAM Role [%s]: %s", roleName, err.Error())
+	}
+	if role == nil {
+		console.AddingResource("Creating IAM Role", roleName, false)
+		role, err = c.awsClient.IAM().CreateRole(core.ECSTasksAssumeRolePolicy, roleName)
+		if err != nil {
+			return "", fmt.Errorf("Failed to create IAM Role [%s]: %s", roleName, err.Error())
+		}
+	}
+
+	// managed policies
+	attachedPolicyARNs, err := c.awsClient.IAM().ListAttachedRolePolicyARNs(roleName)
+	if err != nil {
+		return "", fmt.Errorf("Failed to list policies of IAM Role [%s]: %s", roleName, err.Error())
+	}
+	attached := make(map[string]bool)
+	for _, policyARN := range attachedPolicyARNs {
+		attached[policyARN] = true
+	}
+	configured := make(map[string]bool)
+	for _, policyARN := range c.conf.IAM.ManagedPolicies {
+		configured[policyARN] = true
+		if !attached[policyARN] {
+			console.UpdatingResource("Attaching policy to IAM Role", policyARN, false)
+			if err := c.awsClient.IAM().AttachRolePolicy(policyARN, roleName); err != nil {
+				return "", fmt.Errorf("Failed to attach policy [%s] to IAM Role [%s]: %s", policyARN, roleName, err.Error())
+			}
+		}
+	}
+	for _, policyARN := range attachedPolicyARNs {
+		if !configured[policyARN] {
+			console.RemovingResource("Detaching policy from IAM Role", policyARN, false)
+			if err := c.awsClient.IAM().DetachRolePolicy(policyARN, roleName); err != nil {
+				return "", fmt.Errorf("Failed to detach policy [%s] from IAM Role [%s]: %s", policyARN, roleName, err.Error())
+			}
+		}
+	}
+
+	// inline policy
+	policyName := core.DefaultECSTaskRolePolicyName()
+	policyDocument, err := c.ecsTaskRolePolicyDocument()
+	if err != nil {
+		return "", err
+	}
+	if policyDocument != "" {
+		console.UpdatingResource("Updating policy of IAM Role", roleName, false)
+		if err := c.awsClient.IAM().PutRolePolicy(policyName, policyDocument, roleName); err != nil {
+			return "", fmt.Errorf("Failed to update policy of IAM Role [%s]: %s", roleName, err.Error())
+		}
+	} else {
+		currentDocument, err := c.awsClient.IAM().RetrieveRolePolicyDocument(policyName, roleName)
+		if err != nil {
+			return "", fmt.Errorf("Failed to retrieve policy of IAM Role [%s]: %s", roleName, err.Error())
+		}
+		if currentDocument != "" {
+			console.RemovingResource("Deleting policy of IAM Role", roleName, false)
+			if err := c.awsClient.IAM().DeleteRolePolicy(policyName, roleName); err != nil {
+				return "", fmt.Errorf("Failed to delete policy of IAM Role [%s]: %s", roleName, err.Error())
+			}
+		}
+	}
+
+	return conv.S(role.Arn), nil
+}
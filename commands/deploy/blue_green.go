@@ -0,0 +1,247 @@
+package deploy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_elb "github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/coldbrewcloud/coldbrew-cli/aws/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+// deployBlueGreen deploys the ECS Task Definition to the idle ECS Service, switches the ELB listeners to it
+// once all of its targets are healthy, and stops the previous ECS Service after the bake time.
+// The live ECS Service is not changed unless the new version becomes healthy.
+func (c *Command) deployBlueGreen(ecsTaskDefinitionARN string) error {
+	ecsClusterName := core.DefaultECSClusterName(conv.S(c.conf.ClusterName))
+
+	state, err := commands.RetrieveBlueGreenState(c.awsClient, c.conf)
+	if err != nil {
+		return err
+	}
+
+	// first deployment: create blue ECS Service along with ELB resources
+	if state.Live.ECSService == nil {
+		if _, err := c.createOrUpdateECSService(ecsTaskDefinitionARN); err != nil {
+			return err
+		}
+		return c.waitForDeployment(ecsClusterName, state.Live.ECSServiceName, ecsTaskDefinitionARN)
+	}
+	if len(state.Listeners) == 0 {
+		return fmt.Errorf("ELB Load Balancer [%s] does not have a listener to ELB Target Group [%s].",
+			conv.S(c.conf.AWS.ELBLoadBalancerName), state.Live.ELBTargetGroupName)
+	}
+
+	console.Info(fmt.Sprintf("Blue/green deployment: %s (live) -> %s (idle)", state.Live.ECSServiceName, state.Idle.ECSServiceName))
+
+	// idle ELB Target Group
+	if err := c.prepareBlueGreenTargetGroup(state); err != nil {
+		return err
+	}
+
+	// idle ECS Service
+	if state.Idle.ECSService == nil {
+		loadBalancer := &ecs.LoadBalancer{
+			ELBTargetGroupARN: conv.S(state.Idle.ELBTargetGroup.TargetGroupArn),
+			TaskContainerName: conv.S(c.conf.Name),
+			TaskContainerPort: c.conf.LoadBalancedPort(),
+		}
+		console.AddingResource("Creating ECS Service", state.Idle.ECSServiceName, false)
+		_, err := c.awsClient.ECS().CreateService(
+			ecsClusterName, state.Idle.ECSServiceName, ecsTaskDefinitionARN, c.ecsInitialDesiredCount(state.Live.ECSService),
+			[]*ecs.LoadBalancer{loadBalancer}, core.DefaultECSServiceRoleName(conv.S(c.conf.ClusterName)),
+			c.ecsDeploymentConfiguration(), c.ecsPlacement())
+		if err != nil {
+			return fmt.Errorf("Failed to create ECS Service [%s]: %s", state.Idle.ECSServiceName, err.Error())
+		}
+	} else {
+		console.UpdatingResource("Updating ECS Service", state.Idle.ECSServiceName, false)
+		desiredCount := c.ecsInitialDesiredCount(state.Live.ECSService)
+		_, err := c.awsClient.ECS().UpdateService(ecsClusterName, state.Idle.ECSServiceName, ecsTaskDefinitionARN, &desiredCount, c.ecsDeploymentConfiguration(), c.ecsPlacement())
+		if err != nil {
+			return fmt.Errorf("Failed to update ECS Service [%s]: %s", state.Idle.ECSServiceName, err.Error())
+		}
+	}
+
+	// new version must be healthy before it receives any traffic
+	if err := c.waitForDeployment(ecsClusterName, state.Idle.ECSServiceName, ecsTaskDefinitionARN); err != nil {
+		c.stopECSService(ecsClusterName, state.Idle.ECSServiceName, ecsTaskDefinitionARN)
+		if removeErr := c.removeBlueGreenHeaderRules(state); removeErr != nil {
+			console.Error(removeErr.Error())
+		}
+		return fmt.Errorf("Deployment to ECS Service [%s] failed (traffic was not switched): %s", state.Idle.ECSServiceName, err.Error())
+	}
+
+	if err := commands.SwitchBlueGreenListeners(c.awsClient, state); err != nil {
+		return err
+	}
+
+	// the new live target group is associated with the load balancer by the listeners now
+	if err := c.removeBlueGreenHeaderRules(state); err != nil {
+		return err
+	}
+
+	// bake: previous version keeps running so that traffic can be switched back instantly
+	if err := c.bakeBlueGreen(state); err != nil {
+		if !conv.B(c._commandFlags.Rollback) {
+			return err
+		}
+
+		console.Error(err.Error())
+		if switchErr := commands.SwitchBlueGreenListeners(c.awsClient, state); switchErr != nil {
+			return fmt.Errorf("Failed to switch back to ECS Service [%s]: %s (bake failure: %s)", state.Idle.ECSServiceName, switchErr.Error(), err.Error())
+		}
+		c.stopECSService(ecsClusterName, state.Idle.ECSServiceName, ecsTaskDefinitionARN)
+		return fmt.Errorf("Switched back to ECS Service [%s] because the new version failed during the bake time: %s", state.Live.ECSServiceName, err.Error())
+	}
+
+	// auto scaling moves to the new version before the previous version is stopped
+	if err := commands.UpdateAutoScaling(c.awsClient, c.conf, ecsClusterName, state.Live.ECSServiceName); err != nil {
+		return err
+	}
+
+	// stop previous version
+	c.stopECSService(ecsClusterName, state.Idle.ECSServiceName, conv.S(state.Idle.ECSService.TaskDefinition))
+
+	return nil
+}
+
+// prepareBlueGreenTargetGroup creates the ELB Target Group of the idle slot if needed. ECS requires the target
+// group to be associated with the load balancer before the service is deployed, so a listener rule that only
+// matches requests with core.ELBBlueGreenHeaderName header is added. The rule is temporary: it's removed by
+// removeBlueGreenHeaderRules once the deployment to the idle slot is finished.
+func (c *Command) prepareBlueGreenTargetGroup(state *commands.BlueGreenState) error {
+	idle := state.Idle
+
+	if idle.ELBTargetGroup == nil {
+		console.AddingResource("Creating ELB Target Group", idle.ELBTargetGroupName, false)
+		if _, err := c.createELBTargetGroup(idle.ELBTargetGroupName); err != nil {
+			return err
+		}
+
+		elbTargetGroup, err := c.awsClient.ELB().RetrieveTargetGroupByName(idle.ELBTargetGroupName)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve ELB Target Group [%s]: %s", idle.ELBTargetGroupName, err.Error())
+		}
+		if elbTargetGroup == nil {
+			return fmt.Errorf("ELB Target Group [%s] was not found.", idle.ELBTargetGroupName)
+		}
+		idle.ELBTargetGroup = elbTargetGroup
+	} else {
+		if err := c.checkLoadBalancerHealthCheckChanges(conv.S(idle.ELBTargetGroup.TargetGroupArn)); err != nil {
+			return err
+		}
+	}
+
+	if len(idle.ELBTargetGroup.LoadBalancerArns) > 0 {
+		return nil
+	}
+
+	listenerARN := conv.S(state.Listeners[0].ListenerArn)
+	rules, err := c.awsClient.ELB().RetrieveListenerRules(listenerARN)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve rules of ELB Listener [%s]: %s", listenerARN, err.Error())
+	}
+	priority := int64(1)
+	for _, r := range rules {
+		if p, err := strconv.ParseInt(conv.S(r.Priority), 10, 64); err == nil && p >= priority {
+			priority = p + 1
+		}
+	}
+
+	console.AddingResource(fmt.Sprintf("Adding listener rule [%s: %s] for ELB Load Balancer", core.ELBBlueGreenHeaderName, idle.ELBTargetGroupName),
+		conv.S(state.ELBLoadBalancer.LoadBalancerName), false)
+	err = c.awsClient.ELB().CreateHTTPHeaderRule(listenerARN, conv.S(idle.ELBTargetGroup.TargetGroupArn), priority, core.ELBBlueGreenHeaderName, idle.ELBTargetGroupName)
+	if err != nil {
+		return fmt.Errorf("Failed to create ELB Listener rule: %s", err.Error())
+	}
+
+	return nil
+}
+
+// removeBlueGreenHeaderRules deletes the listener rules added by prepareBlueGreenTargetGroup, so that the idle
+// slot cannot be reached with core.ELBBlueGreenHeaderName header.
+func (c *Command) removeBlueGreenHeaderRules(state *commands.BlueGreenState) error {
+	for _, l := range state.Listeners {
+		listenerARN := conv.S(l.ListenerArn)
+		rules, err := c.awsClient.ELB().RetrieveListenerRules(listenerARN)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve rules of ELB Listener [%s]: %s", listenerARN, err.Error())
+		}
+		for _, r := range rules {
+			if !isBlueGreenHeaderRule(r) {
+				continue
+			}
+			console.RemovingResource("Deleting ELB Listener rule", conv.S(r.RuleArn), false)
+			if err := c.awsClient.ELB().DeleteRule(conv.S(r.RuleArn)); err != nil {
+				return fmt.Errorf("Failed to delete ELB Listener rule [%s]: %s", conv.S(r.RuleArn), err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+func isBlueGreenHeaderRule(rule *_elb.Rule) bool {
+	if conv.B(rule.IsDefault) {
+		return false
+	}
+	for _, condition := range rule.Conditions {
+		if conv.S(condition.Field) == "http-header" && condition.HttpHeaderConfig != nil &&
+			strings.EqualFold(conv.S(condition.HttpHeaderConfig.HttpHeaderName), core.ELBBlueGreenHeaderName) {
+			return true
+		}
+	}
+	return false
+}
+
+// bakeBlueGreen watches the targets of the live slot for the bake time and returns an error as soon as
+// any of them becomes unhealthy.
+func (c *Command) bakeBlueGreen(state *commands.BlueGreenState) error {
+	bakeSeconds, err := core.ParseTimeExpression(conv.S(c.conf.LoadBalancer.BlueGreen.BakeTime))
+	if err != nil {
+		return err
+	}
+	if bakeSeconds == 0 {
+		return nil
+	}
+
+	bakeTime := time.Duration(bakeSeconds) * time.Second
+	console.ProcessingOnResource(fmt.Sprintf("Baking for %s (previous version: %s)", bakeTime.String(), state.Idle.ECSServiceName), state.Live.ECSServiceName, true)
+
+	elbTargetGroupARN := conv.S(state.Live.ELBTargetGroup.TargetGroupArn)
+	deadline := time.Now().Add(bakeTime)
+	for time.Now().Before(deadline) {
+		targets, err := c.awsClient.ELB().RetrieveTargetHealth(elbTargetGroupARN)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve target health of ELB Target Group [%s]: %s", state.Live.ELBTargetGroupName, err.Error())
+		}
+		for _, t := range targets {
+			if t.TargetHealth != nil && conv.S(t.TargetHealth.State) == "unhealthy" {
+				return fmt.Errorf("ELB Target [%s:%d] became unhealthy: %s",
+					conv.S(t.Target.Id), conv.I64(t.Target.Port), conv.S(t.TargetHealth.Description))
+			}
+		}
+
+		time.Sleep(waitPollInterval)
+	}
+
+	return nil
+}
+
+// stopECSService deregisters the ECS Service from auto scaling and sets its desired count to 0. Errors are
+// only reported because the service is not serving any traffic.
+func (c *Command) stopECSService(ecsClusterName, ecsServiceName, ecsTaskDefinitionARN string) {
+	if err := commands.DeregisterAutoScaling(c.awsClient, ecsClusterName, ecsServiceName); err != nil {
+		console.Error(err.Error())
+	}
+
+	console.UpdatingResource("Updating ECS Service to stop all tasks", ecsServiceName, false)
+	if _, err := c.awsClient.ECS().UpdateService(ecsClusterName, ecsServiceName, ecsTaskDefinitionARN, conv.U16P(0), nil, nil); err != nil {
+		console.Error(fmt.Sprintf("Failed to update ECS Service [%s]: %s", ecsServiceName, err.Error()))
+	}
+}
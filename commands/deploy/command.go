@@ -3,8 +3,10 @@ package deploy
 import (
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 
 	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
 	"github.com/coldbrewcloud/coldbrew-cli/config"
 	"github.com/coldbrewcloud/coldbrew-cli/console"
 	"github.com/coldbrewcloud/coldbrew-cli/core"
@@ -47,7 +49,7 @@ func (c *Command) Run() error {
 	if err != nil {
 		return console.ExitWithErrorString("Failed to read configuration file [%s]: %s", configFilePath, err.Error())
 	}
-	c.conf, err = config.Load(configData, conv.S(c.globalFlags.ConfigFileFormat), core.DefaultAppName(configFilePath))
+	c.conf, err = config.Load(configData, conv.S(c.globalFlags.ConfigFileFormat), core.DefaultAppName(configFilePath), conv.S(c.globalFlags.Environment), filepath.Dir(configFilePath))
 	if err != nil {
 		return console.ExitWithError(err)
 	}
@@ -65,10 +67,18 @@ func (c *Command) Run() error {
 
 	// test if target cluster is available to use
 	console.ProcessingOnResource("Checking cluster availability", conv.S(c.conf.ClusterName), false)
-	if err := c.isClusterAvailable(conv.S(c.conf.ClusterName)); err != nil {
+	if err := commands.CheckClusterAvailability(c.awsClient, conv.S(c.conf.ClusterName)); err != nil {
 		return console.ExitWithError(core.NewErrorExtraInfo(err, "https://github.com/coldbrewcloud/coldbrew-cli/wiki/Error:-Cluster-not-found"))
 	}
 
+	// print what would be changed and stop
+	if conv.B(c._commandFlags.Plan) {
+		if err := c.plan(); err != nil {
+			return console.ExitWithError(err)
+		}
+		return nil
+	}
+
 	// docker client
 	c.dockerClient = docker.NewClient(conv.S(c.conf.Docker.Bin))
 	if !c.dockerClient.DockerBinAvailable() {
@@ -86,7 +96,11 @@ func (c *Command) Run() error {
 	// prepare docker image (build one if needed)
 	dockerImage := conv.S(c._commandFlags.DockerImage)
 	if utils.IsBlank(dockerImage) { // build local docker image
-		dockerImage = fmt.Sprintf("%s:latest", ecrRepoURI)
+		imageTag, err := c.dockerImageTag()
+		if err != nil {
+			return console.ExitWithError(err)
+		}
+		dockerImage = fmt.Sprintf("%s:%s", ecrRepoURI, imageTag)
 		console.ProcessingOnResource("Building Docker image", dockerImage, true)
 		if err := c.buildDockerImage(dockerImage); err != nil {
 			return console.ExitWithError(err)
@@ -118,14 +132,58 @@ func (c *Command) Run() error {
 		return console.ExitWithError(err)
 	}
 
+	// optionally, update "latest" tag too (task definition still refers to the immutable tag)
+	if conv.B(c._commandFlags.TagLatest) {
+		latestImage := fmt.Sprintf("%s:latest", ecrRepoURI)
+		if latestImage != dockerImage {
+			console.AddingResource("Tagging Docker image", fmt.Sprintf("%s -> %s", dockerImage, latestImage), false)
+			if err := c.dockerClient.TagImage(dockerImage, latestImage); err != nil {
+				return console.ExitWithError(err)
+			}
+			console.ProcessingOnResource("Pushing Docker image", latestImage, true)
+			if err := c.dockerClient.PushImage(latestImage); err != nil {
+				return console.ExitWithErrorString("Failed to push Docker image [%s]: %s", latestImage, err.Error())
+			}
+		}
+	}
+
 	// create/update ECS task definition
 	ecsTaskDefinitionARN, err := c.updateECSTaskDefinition(dockerImage)
 	if err != nil {
 		return console.ExitWithError(err)
 	}
 
-	// create/update ECS service
-	if err := c.createOrUpdateECSService(ecsTaskDefinitionARN); err != nil {
+	if conv.B(c.conf.LoadBalancer.BlueGreen.Enabled) {
+		// blue/green deployment (always waits until the new version becomes healthy)
+		if err := c.deployBlueGreen(ecsTaskDefinitionARN); err != nil {
+			return console.ExitWithError(err)
+		}
+	} else {
+		// create/update ECS service
+		previousECSTaskDefinitionARN, err := c.createOrUpdateECSService(ecsTaskDefinitionARN)
+		if err != nil {
+			return console.ExitWithError(err)
+		}
+
+		// wait until the deployment becomes stable (roll back if it fails)
+		if conv.B(c._commandFlags.Wait) {
+			ecsClusterName := core.DefaultECSClusterName(conv.S(c.conf.ClusterName))
+			ecsServiceName := core.DefaultECSServiceName(conv.S(c.conf.Name))
+			if err := c.waitForDeployment(ecsClusterName, ecsServiceName, ecsTaskDefinitionARN); err != nil {
+				if _, ok := err.(*circuitBreakerError); ok && conv.B(c.conf.Deployment.CircuitBreaker.Rollback) {
+					// ECS deployment circuit breaker rolls back the deployments it failed by itself
+					return console.ExitWithError(err)
+				}
+				if conv.B(c._commandFlags.Rollback) && previousECSTaskDefinitionARN != "" && previousECSTaskDefinitionARN != ecsTaskDefinitionARN {
+					return console.ExitWithError(c.rollbackECSService(ecsClusterName, ecsServiceName, previousECSTaskDefinitionARN, ecsTaskDefinitionARN, err))
+				}
+				return console.ExitWithError(err)
+			}
+		}
+	}
+
+	// scheduled tasks run the deployed ECS Task Definition
+	if err := c.updateSchedules(ecsTaskDefinitionARN); err != nil {
 		return console.ExitWithError(err)
 	}
 
@@ -135,30 +193,6 @@ func (c *Command) Run() error {
 	return nil
 }
 
-func (c *Command) isClusterAvailable(clusterName string) error {
-	// check ECS cluster
-	ecsClusterName := core.DefaultECSClusterName(clusterName)
-	ecsCluster, err := c.awsClient.ECS().RetrieveCluster(ecsClusterName)
-	if err != nil {
-		return fmt.Errorf("Failed to retrieve ECS Cluster [%s]: %s", ecsClusterName, err.Error())
-	}
-	if ecsCluster == nil || conv.S(ecsCluster.Status) == "INACTIVE" {
-		return fmt.Errorf("ECS Cluster [%s] not found", ecsClusterName)
-	}
-
-	// check ECS service role
-	ecsServiceRoleName := core.DefaultECSServiceRoleName(clusterName)
-	ecsServiceRole, err := c.awsClient.IAM().RetrieveRole(ecsServiceRoleName)
-	if err != nil {
-		return fmt.Errorf("Failed to retrieve IAM Role [%s]: %s", ecsServiceRoleName, err.Error())
-	}
-	if ecsServiceRole == nil {
-		return fmt.Errorf("IAM Role [%s] not found", ecsServiceRoleName)
-	}
-
-	return nil
-}
-
 func (c *Command) prepareECRRepo(repoName string) (string, error) {
 	ecrRepo, err := c.awsClient.ECR().RetrieveRepository(repoName)
 	if err != nil {
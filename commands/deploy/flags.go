@@ -20,6 +20,12 @@ type Flags struct {
 	CPU            *float64           `json:"cpu,omitempty"`
 	Memory         *string            `json:"memory,omitempty"`
 	Envs           *map[string]string `json:"env,omitempty"`
+	Wait           *bool              `json:"wait,omitempty"`
+	WaitTimeout    *string            `json:"wait-timeout,omitempty"`
+	Rollback       *bool              `json:"rollback,omitempty"`
+	Plan           *bool              `json:"plan,omitempty"`
+	ImageTag       *string            `json:"image-tag,omitempty"`
+	TagLatest      *bool              `json:"tag-latest,omitempty"`
 }
 
 func NewFlags(kc *kingpin.CmdClause) *Flags {
@@ -30,6 +36,12 @@ func NewFlags(kc *kingpin.CmdClause) *Flags {
 		CPU:            kc.Flag("cpu", "Docker CPU resource (1 unit: 1024)").Default("-1").Float64(),
 		Memory:         kc.Flag("memory", "Docker memory resource").Default("").String(),
 		Envs:           kc.Flag("env", "Environment variable (\"key=value\")").Short('E').StringMap(),
+		Wait:           kc.Flag("wait", "Wait until the deployment becomes stable").Bool(),
+		WaitTimeout:    kc.Flag("wait-timeout", "Maximum time to wait for the deployment (with --wait)").Default("10m").String(),
+		Rollback:       kc.Flag("rollback", "Roll back if the deployment fails (with --wait, or during blue/green bake time)").Default("true").Bool(),
+		Plan:           kc.Flag("plan", "Show what would be changed without making any changes").Bool(),
+		ImageTag:       kc.Flag("image-tag", "Tag template for built Docker image (e.g. \"{short_sha}{dirty}\")").Default("").String(),
+		TagLatest:      kc.Flag("tag-latest", "Also tag and push Docker image as \"latest\"").Bool(),
 	}
 }
 
@@ -46,6 +58,10 @@ func (c *Command) mergeFlagsIntoConfiguration(conf *config.Config, flags *Flags)
 		conf.Memory = conv.SP(conv.S(flags.Memory))
 	}
 
+	if !utils.IsBlank(conv.S(flags.ImageTag)) {
+		conf.Docker.ImageTag = conv.SP(conv.S(flags.ImageTag))
+	}
+
 	// envs
 	for ek, ev := range *flags.Envs {
 		conf.Env[ek] = ev
@@ -77,6 +93,14 @@ func (c *Command) validateFlags(flags *Flags) error {
 		return fmt.Errorf("Invalid app memory [%s]", conv.S(flags.Memory))
 	}
 
+	if !utils.IsBlank(conv.S(flags.ImageTag)) && !core.DockerImageTagTemplateRE.MatchString(conv.S(flags.ImageTag)) {
+		return fmt.Errorf("Invalid Docker image tag [%s]", conv.S(flags.ImageTag))
+	}
+
+	if conv.B(flags.Wait) && !core.TimeExpressionRE.MatchString(conv.S(flags.WaitTimeout)) {
+		return fmt.Errorf("Invalid wait timeout [%s]", conv.S(flags.WaitTimeout))
+	}
+
 	return nil
 }
 
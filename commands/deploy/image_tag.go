@@ -0,0 +1,61 @@
+package deploy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/git"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+// dockerImageTag returns the tag for a locally built Docker image. If the tag template refers to
+// git commit information but the app directory is not a git working tree, build timestamp is used instead.
+func (c *Command) dockerImageTag() (string, error) {
+	template := conv.S(c.conf.Docker.ImageTag)
+	now := time.Now()
+
+	if core.DockerImageTagUsesGit(template) {
+		gitSHA, dirty, err := c.gitCommit()
+		if err != nil {
+			tag := core.RenderDockerImageTag("{timestamp}", "", false, now)
+			console.DetailWithResourceNote("Image tag", tag, fmt.Sprintf("(git commit not available: %s)", err.Error()), true)
+			return tag, nil
+		}
+
+		return c.validateDockerImageTag(core.RenderDockerImageTag(template, gitSHA, dirty, now))
+	}
+
+	return c.validateDockerImageTag(core.RenderDockerImageTag(template, "", false, now))
+}
+
+func (c *Command) gitCommit() (string, bool, error) {
+	appDir, err := c.globalFlags.GetApplicationDirectory()
+	if err != nil {
+		return "", false, err
+	}
+
+	gitClient := git.NewClient("git")
+	if !gitClient.GitBinAvailable() {
+		return "", false, fmt.Errorf("git binary not found")
+	}
+
+	gitSHA, err := gitClient.CommitSHA(appDir)
+	if err != nil {
+		return "", false, err
+	}
+	dirty, err := gitClient.IsDirty(appDir)
+	if err != nil {
+		return "", false, err
+	}
+
+	return gitSHA, dirty, nil
+}
+
+func (c *Command) validateDockerImageTag(tag string) (string, error) {
+	if !core.DockerImageTagRE.MatchString(tag) {
+		return "", fmt.Errorf("Invalid Docker image tag [%s]", tag)
+	}
+	return tag, nil
+}
@@ -0,0 +1,903 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	_aws "github.com/aws/aws-sdk-go/aws"
+	_aas "github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/aws/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/utils"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+type configChange struct {
+	name   string
+	before string
+	after  string
+}
+
+func appendChange(changes []configChange, name, before, after string) []configChange {
+	if before == after {
+		return changes
+	}
+	return append(changes, configChange{name: name, before: before, after: after})
+}
+
+func printChanges(changes []configChange) {
+	for _, c := range changes {
+		switch {
+		case c.before == "":
+			console.DetailWithResourceNote(c.name, c.after, "(added)", false)
+		case c.after == "":
+			console.DetailWithResourceNote(c.name, c.before, "(removed)", true)
+		default:
+			console.PlanChange(c.name, c.before, c.after)
+		}
+	}
+}
+
+// envChanges returns the differences between two sets of environment variables, sorted by name.
+func envChanges(before, after map[string]string) []configChange {
+	names := []string{}
+	for k := range before {
+		names = append(names, k)
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []configChange{}
+	for _, name := range names {
+		b, a := "", ""
+		if v, ok := before[name]; ok {
+			b = fmt.Sprintf("%q", v)
+		}
+		if v, ok := after[name]; ok {
+			a = fmt.Sprintf("%q", v)
+		}
+		changes = appendChange(changes, "Env "+name, b, a)
+	}
+	return changes
+}
+
+// plan prints what deploy would create or update, without making any changes to AWS resources.
+func (c *Command) plan() error {
+	console.Info("Deployment plan (no changes will be made)")
+
+	// ECR repository
+	ecrRepoName := conv.S(c.conf.AWS.ECRRepositoryName)
+	ecrRepo, err := c.awsClient.ECR().RetrieveRepository(ecrRepoName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve ECR repository [%s]: %s", ecrRepoName, err.Error())
+	}
+	ecrRepoURI := ecrRepoName
+	if ecrRepo == nil {
+		console.PlanAddResource("ECR Repository", ecrRepoName)
+	} else {
+		ecrRepoURI = conv.S(ecrRepo.RepositoryUri)
+		console.PlanNoChangeResource("ECR Repository", ecrRepoName)
+	}
+
+	// docker image
+	dockerImage := conv.S(c._commandFlags.DockerImage)
+	if utils.IsBlank(dockerImage) {
+		imageTag, err := c.dockerImageTag()
+		if err != nil {
+			return err
+		}
+		dockerImage = fmt.Sprintf("%s:%s", ecrRepoURI, imageTag)
+		console.PlanAddResource("Docker image (build and push)", dockerImage)
+	} else {
+		console.PlanAddResource("Docker image (push)", dockerImage)
+	}
+	if conv.B(c._commandFlags.TagLatest) {
+		console.PlanUpdateResource("Docker image tag", fmt.Sprintf("%s:latest", ecrRepoURI))
+	}
+
+	// CloudWatch Logs group
+	if conv.S(c.conf.Logging.Driver) == aws.ECSTaskDefinitionLogDriverAWSLogs {
+		if err := c.planCloudWatchLogsGroup(); err != nil {
+			return err
+		}
+	}
+
+	// ECS service
+	ecsClusterName := core.DefaultECSClusterName(conv.S(c.conf.ClusterName))
+	ecsServiceName := core.DefaultECSServiceName(conv.S(c.conf.Name))
+	ecsService, err := c.awsClient.ECS().RetrieveService(ecsClusterName, ecsServiceName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve ECS Service [%s/%s]: %s", ecsClusterName, ecsServiceName, err.Error())
+	}
+	if ecsService != nil && conv.S(ecsService.Status) != "ACTIVE" {
+		ecsService = nil
+	}
+
+	// blue/green: compare with the live ECS Service
+	var blueGreenState *commands.BlueGreenState
+	if conv.B(c.conf.LoadBalancer.BlueGreen.Enabled) {
+		blueGreenState, err = commands.RetrieveBlueGreenState(c.awsClient, c.conf)
+		if err != nil {
+			return err
+		}
+		ecsService = blueGreenState.Live.ECSService
+	}
+
+	// IAM role for secrets
+	if len(c.secretResourceARNs()) > 0 {
+		roleName := core.DefaultECSTaskExecutionRoleName(conv.S(c.conf.ClusterName), conv.S(c.conf.Name))
+		role, err := c.awsClient.IAM().RetrieveRole(roleName)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve IAM Role [%s]: %s", roleName, err.Error())
+		}
+		if role == nil {
+			console.PlanAddResource("IAM Role (secrets)", roleName)
+		} else {
+			console.PlanUpdateResource("IAM Role (secrets)", roleName)
+		}
+	}
+
+	// IAM role for tasks
+	if err := c.planECSTaskRole(); err != nil {
+		return err
+	}
+
+	// ECS task definition
+	if err := c.planECSTaskDefinition(ecsService, dockerImage); err != nil {
+		return err
+	}
+
+	if ecsService == nil {
+		console.PlanAddResource("ECS Service", ecsServiceName)
+		console.DetailWithResource("Units", fmt.Sprintf("%d", c.ecsInitialDesiredCount(nil)))
+		c.planPlacement(nil)
+
+		if conv.B(c.conf.LoadBalancer.Enabled) {
+			if err := c.planELBLoadBalancer(); err != nil {
+				return err
+			}
+		}
+	} else if blueGreenState != nil {
+		c.planBlueGreen(blueGreenState)
+	} else {
+		console.PlanUpdateResource("ECS Service", ecsServiceName)
+		if desiredCount := c.ecsDesiredCount(); desiredCount != nil {
+			printChanges(appendChange(nil, "Units",
+				fmt.Sprintf("%d", conv.I64(ecsService.DesiredCount)), fmt.Sprintf("%d", *desiredCount)))
+		}
+		printChanges(c.deploymentConfigurationChanges(ecsService.DeploymentConfiguration))
+		c.planPlacement(ecsService)
+
+		if len(ecsService.LoadBalancers) > 0 {
+			if conv.I64(ecsService.LoadBalancers[0].ContainerPort) != int64(c.conf.LoadBalancedPort()) {
+				console.DetailWithResourceNote("Port", fmt.Sprintf("%d", c.conf.LoadBalancedPort()), "(load balanced app port cannot be changed: deploy will fail)", true)
+			}
+
+			if err := c.planELBTargetGroupHealthCheck(conv.S(ecsService.LoadBalancers[0].TargetGroupArn)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// scheduled tasks
+	if err := c.planSchedules(); err != nil {
+		return err
+	}
+
+	// auto scaling (blue/green: moves from the live ECS Service to the new one)
+	if ecsService != nil {
+		if err := c.planAutoScaling(ecsClusterName, conv.S(ecsService.ServiceName)); err != nil {
+			return err
+		}
+	} else if conv.B(c.conf.AutoScaling.Enabled) {
+		console.PlanAddResource("Auto Scaling Target", ecsServiceName)
+		printChanges(c.autoScalingChanges(nil, nil))
+	}
+
+	console.Blank()
+	console.Info("Run deploy without --plan to apply these changes.")
+
+	return nil
+}
+
+func (c *Command) planBlueGreen(state *commands.BlueGreenState) {
+	currentUnits := int64(0)
+	if state.Idle.ECSService == nil {
+		console.PlanAddResource("ECS Service (idle)", state.Idle.ECSServiceName)
+	} else {
+		currentUnits = conv.I64(state.Idle.ECSService.DesiredCount)
+		console.PlanUpdateResource("ECS Service (idle)", state.Idle.ECSServiceName)
+	}
+	printChanges(appendChange(nil, "Units", fmt.Sprintf("%d", currentUnits), fmt.Sprintf("%d", c.ecsInitialDesiredCount(state.Live.ECSService))))
+	c.planPlacement(state.Idle.ECSService)
+
+	if state.Idle.ELBTargetGroup == nil {
+		console.PlanAddResource("ELB Target Group", state.Idle.ELBTargetGroupName)
+	} else {
+		console.PlanNoChangeResource("ELB Target Group", state.Idle.ELBTargetGroupName)
+	}
+
+	for _, l := range state.Listeners {
+		console.PlanUpdateResource(fmt.Sprintf("ELB Listener (%s:%d)", conv.S(l.Protocol), conv.I64(l.Port)),
+			fmt.Sprintf("%s -> %s", state.Live.ELBTargetGroupName, state.Idle.ELBTargetGroupName))
+	}
+
+	console.PlanUpdateResource("ECS Service (live)", state.Live.ECSServiceName)
+	printChanges(appendChange(nil, "Units",
+		fmt.Sprintf("%d", conv.I64(state.Live.ECSService.DesiredCount)), "0"))
+	console.DetailWithResource("Bake time", conv.S(c.conf.LoadBalancer.BlueGreen.BakeTime))
+}
+
+// deploymentConfigurationChanges returns the differences between the deployment configuration of the ECS Service
+// and the app configuration.
+func (c *Command) deploymentConfigurationChanges(current *_ecs.DeploymentConfiguration) []configChange {
+	if current == nil {
+		current = &_ecs.DeploymentConfiguration{}
+	}
+	currentCircuitBreaker := &_ecs.DeploymentCircuitBreaker{}
+	if current.DeploymentCircuitBreaker != nil {
+		currentCircuitBreaker = current.DeploymentCircuitBreaker
+	}
+
+	changes := []configChange{}
+	changes = appendChange(changes, "Max Percent",
+		fmt.Sprintf("%d%%", conv.I64(current.MaximumPercent)), fmt.Sprintf("%d%%", conv.U16(c.conf.Deployment.MaxPercent)))
+	changes = appendChange(changes, "Min Healthy Percent",
+		fmt.Sprintf("%d%%", conv.I64(current.MinimumHealthyPercent)), fmt.Sprintf("%d%%", conv.U16(c.conf.Deployment.MinHealthyPercent)))
+	changes = appendChange(changes, "Circuit Breaker",
+		fmt.Sprintf("%t", conv.B(currentCircuitBreaker.Enable)), fmt.Sprintf("%t", conv.B(c.conf.Deployment.CircuitBreaker.Enabled)))
+	changes = appendChange(changes, "Circuit Breaker Rollback",
+		fmt.Sprintf("%t", conv.B(currentCircuitBreaker.Rollback)), fmt.Sprintf("%t", conv.B(c.conf.Deployment.CircuitBreaker.Rollback)))
+
+	return changes
+}
+
+// planAutoScaling prints the changes to the auto scaling target and policies of the ECS Service.
+func (c *Command) planAutoScaling(ecsClusterName, ecsServiceName string) error {
+	scalableTarget, err := c.awsClient.ApplicationAutoScaling().RetrieveECSServiceScalableTarget(ecsClusterName, ecsServiceName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve auto scaling target for ECS Service [%s]: %s", ecsServiceName, err.Error())
+	}
+
+	if !conv.B(c.conf.AutoScaling.Enabled) {
+		if scalableTarget != nil {
+			console.PlanRemoveResource("Auto Scaling Target", ecsServiceName)
+		}
+		return nil
+	}
+
+	if scalableTarget == nil {
+		console.PlanAddResource("Auto Scaling Target", ecsServiceName)
+		printChanges(c.autoScalingChanges(nil, nil))
+		return nil
+	}
+
+	policies, err := c.awsClient.ApplicationAutoScaling().RetrieveECSServiceScalingPolicies(ecsClusterName, ecsServiceName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve auto scaling policies for ECS Service [%s]: %s", ecsServiceName, err.Error())
+	}
+	changes := c.autoScalingChanges(scalableTarget, policies)
+	if len(changes) == 0 {
+		console.PlanNoChangeResource("Auto Scaling Target", ecsServiceName)
+	} else {
+		console.PlanUpdateResource("Auto Scaling Target", ecsServiceName)
+		printChanges(changes)
+	}
+
+	return nil
+}
+
+// autoScalingChanges returns the differences between the current scalable target and its scaling policies
+// (nil if not registered) and the auto scaling configuration.
+func (c *Command) autoScalingChanges(current *_aas.ScalableTarget, currentPolicies []*_aas.ScalingPolicy) []configChange {
+	changes := []configChange{}
+
+	minUnits, maxUnits := "", ""
+	if current != nil {
+		minUnits, maxUnits = fmt.Sprintf("%d", conv.I64(current.MinCapacity)), fmt.Sprintf("%d", conv.I64(current.MaxCapacity))
+	}
+	changes = appendChange(changes, "Min Units", minUnits, fmt.Sprintf("%d", conv.U16(c.conf.AutoScaling.MinUnits)))
+	changes = appendChange(changes, "Max Units", maxUnits, fmt.Sprintf("%d", conv.U16(c.conf.AutoScaling.MaxUnits)))
+
+	currentTargets := make(map[string]string)
+	for _, p := range currentPolicies {
+		if p.TargetTrackingScalingPolicyConfiguration != nil {
+			currentTargets[conv.S(p.PolicyName)] = fmt.Sprintf("%.0f", conv.F64(p.TargetTrackingScalingPolicyConfiguration.TargetValue))
+		}
+	}
+	for _, t := range []struct {
+		name   string
+		metric string
+		target uint16
+	}{
+		{"CPU Target", "cpu", conv.U16(c.conf.AutoScaling.CPUTarget)},
+		{"Memory Target", "memory", conv.U16(c.conf.AutoScaling.MemoryTarget)},
+		{"Requests Target", "requests", conv.U16(c.conf.AutoScaling.RequestsTarget)},
+	} {
+		target := ""
+		if t.target > 0 {
+			target = fmt.Sprintf("%d", t.target)
+		}
+		changes = appendChange(changes, t.name, currentTargets[core.DefaultECSServiceScalingPolicyName(t.metric)], target)
+	}
+
+	return changes
+}
+
+// planPlacement prints the task placement changes of the ECS Service (ecsService is nil if it's a new service).
+func (c *Command) planPlacement(ecsService *_ecs.Service) {
+	if ecsService == nil {
+		ecsService = &_ecs.Service{}
+	}
+	printChanges(placementChanges(ecsService, c.ecsPlacement()))
+}
+
+// placementChanges returns the differences between the task placement of the ECS Service and placement.
+func placementChanges(current *_ecs.Service, placement *ecs.Placement) []configChange {
+	currentStrategies, strategies := []string{}, []string{}
+	for _, s := range current.PlacementStrategy {
+		currentStrategies = append(currentStrategies, placementString(conv.S(s.Type), conv.S(s.Field)))
+	}
+	for _, s := range placement.Strategies {
+		strategies = append(strategies, placementString(s.Type, s.Field))
+	}
+
+	currentConstraints, constraints := []string{}, []string{}
+	for _, pc := range current.PlacementConstraints {
+		currentConstraints = append(currentConstraints, placementString(conv.S(pc.Type), conv.S(pc.Expression)))
+	}
+	for _, pc := range placement.Constraints {
+		constraints = append(constraints, placementString(pc.Type, pc.Expression))
+	}
+
+	changes := []configChange{}
+	changes = appendChange(changes, "Placement Strategy", strings.Join(currentStrategies, ", "), strings.Join(strategies, ", "))
+	changes = appendChange(changes, "Placement Constraints", strings.Join(currentConstraints, ", "), strings.Join(constraints, ", "))
+	return changes
+}
+
+func placementString(placementType, value string) string {
+	if value == "" {
+		return placementType
+	}
+	return fmt.Sprintf("%s(%s)", placementType, value)
+}
+
+func (c *Command) planECSTaskRole() error {
+	roleName := core.DefaultECSTaskRoleName(conv.S(c.conf.ClusterName), conv.S(c.conf.Name))
+	role, err := c.awsClient.IAM().RetrieveRole(roleName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve IAM Role [%s]: %s", roleName, err.Error())
+	}
+	if role == nil {
+		console.PlanAddResource("IAM Role (tasks)", roleName)
+		changes, err := c.ecsTaskRoleChanges(nil, "")
+		if err != nil {
+			return err
+		}
+		printChanges(changes)
+		return nil
+	}
+
+	attachedPolicyARNs, err := c.awsClient.IAM().ListAttachedRolePolicyARNs(roleName)
+	if err != nil {
+		return fmt.Errorf("Failed to list policies of IAM Role [%s]: %s", roleName, err.Error())
+	}
+	currentPolicyDocument, err := c.awsClient.IAM().RetrieveRolePolicyDocument(core.DefaultECSTaskRolePolicyName(), roleName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve policy of IAM Role [%s]: %s", roleName, err.Error())
+	}
+
+	changes, err := c.ecsTaskRoleChanges(attachedPolicyARNs, currentPolicyDocument)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		console.PlanNoChangeResource("IAM Role (tasks)", roleName)
+	} else {
+		console.PlanUpdateResource("IAM Role (tasks)", roleName)
+		printChanges(changes)
+	}
+	return nil
+}
+
+// ecsTaskRoleChanges returns the differences between the current managed policies and inline policy
+// document of the task role and the configuration.
+func (c *Command) ecsTaskRoleChanges(attachedPolicyARNs []string, currentPolicyDocument string) ([]configChange, error) {
+	attached := make(map[string]bool)
+	for _, policyARN := range attachedPolicyARNs {
+		attached[policyARN] = true
+	}
+	configured := make(map[string]bool)
+	for _, policyARN := range c.conf.IAM.ManagedPolicies {
+		configured[policyARN] = true
+	}
+
+	changes := []configChange{}
+	for _, policyARN := range c.conf.IAM.ManagedPolicies {
+		if !attached[policyARN] {
+			changes = appendChange(changes, "Managed Policy", "", policyARN)
+		}
+	}
+	for _, policyARN := range attachedPolicyARNs {
+		if !configured[policyARN] {
+			changes = appendChange(changes, "Managed Policy", policyARN, "")
+		}
+	}
+
+	policyDocument, err := c.ecsTaskRolePolicyDocument()
+	if err != nil {
+		return nil, err
+	}
+	changes = appendChange(changes, "Policy Statements", policyStatementsString(currentPolicyDocument), policyStatementsString(policyDocument))
+
+	return changes, nil
+}
+
+// policyStatementsString formats the statements of the policy document (or returns the document as is
+// if it was not created by deploy).
+func policyStatementsString(policyDocument string) string {
+	if policyDocument == "" {
+		return ""
+	}
+
+	policy := iamPolicyDocument{}
+	if err := json.Unmarshal([]byte(policyDocument), &policy); err != nil {
+		return policyDocument
+	}
+	statements := []string{}
+	for _, s := range policy.Statement {
+		statements = append(statements, fmt.Sprintf("%s %s on %s", s.Effect, strings.Join(s.Action, ","), strings.Join(s.Resource, ",")))
+	}
+	return strings.Join(statements, "; ")
+}
+
+func (c *Command) planCloudWatchLogsGroup() error {
+	groupName, ok := c.conf.Logging.Options["awslogs-group"]
+	if !ok || utils.IsBlank(groupName) {
+		groupName = core.DefaultCloudWatchLogsGroupName(conv.S(c.conf.Name), conv.S(c.conf.ClusterName))
+	}
+
+	groups, err := c.awsClient.CloudWatchLogs().ListGroups(groupName)
+	if err != nil {
+		return fmt.Errorf("Failed to list CloudWatch Logs Group [%s]: %s", groupName, err.Error())
+	}
+	for _, group := range groups {
+		if conv.S(group.LogGroupName) == groupName {
+			console.PlanNoChangeResource("CloudWatch Logs Group", groupName)
+			return nil
+		}
+	}
+
+	console.PlanAddResource("CloudWatch Logs Group", groupName)
+	return nil
+}
+
+func (c *Command) planECSTaskDefinition(ecsService *_ecs.Service, dockerImage string) error {
+	ecsTaskDefinitionName := core.DefaultECSTaskDefinitionName(conv.S(c.conf.Name))
+	containers, err := c.ecsContainerDefinitions(dockerImage)
+	if err != nil {
+		return err
+	}
+	volumes := c.ecsVolumes()
+
+	if ecsService == nil {
+		console.PlanAddResource("ECS Task Definition", ecsTaskDefinitionName)
+		printChanges(volumeChanges(nil, volumes))
+		for _, container := range containers {
+			console.PlanAddResource("Container", container.Name)
+			printChanges(containerChanges(nil, container))
+		}
+		return nil
+	}
+
+	currentARN := conv.S(ecsService.TaskDefinition)
+	ecsTaskDef, err := c.awsClient.ECS().RetrieveTaskDefinition(currentARN)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve ECS Task Definition [%s]: %s", currentARN, err.Error())
+	}
+
+	console.PlanUpdateResource("ECS Task Definition (new revision)", aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(currentARN))
+	printChanges(volumeChanges(ecsTaskDef.Volumes, volumes))
+
+	for _, container := range containers {
+		var current *_ecs.ContainerDefinition
+		for _, cd := range ecsTaskDef.ContainerDefinitions {
+			if conv.S(cd.Name) == container.Name {
+				current = cd
+				break
+			}
+		}
+		if current == nil {
+			console.PlanAddResource("Container", container.Name)
+			printChanges(containerChanges(nil, container))
+			continue
+		}
+
+		changes := containerChanges(current, container)
+		if len(changes) == 0 {
+			console.PlanNoChangeResource("Container", container.Name)
+		} else {
+			console.PlanUpdateResource("Container", container.Name)
+			printChanges(changes)
+		}
+		if conv.S(current.Image) == container.Image && container.Name == core.DefaultECSTaskMainContainerName(conv.S(c.conf.Name)) {
+			console.DetailWithResourceNote("Image", container.Image, "(same tag)", false)
+		}
+	}
+
+	for _, cd := range ecsTaskDef.ContainerDefinitions {
+		found := false
+		for _, container := range containers {
+			if conv.S(cd.Name) == container.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			console.PlanRemoveResource("Container", conv.S(cd.Name))
+		}
+	}
+
+	return nil
+}
+
+// volumeChanges returns the differences between the current volumes of the task definition and the volumes
+// that deploy would register, sorted by name.
+func volumeChanges(current []*_ecs.Volume, volumes []*ecs.Volume) []configChange {
+	before := make(map[string]string)
+	for _, v := range current {
+		volume := &ecs.Volume{Name: conv.S(v.Name)}
+		switch {
+		case v.DockerVolumeConfiguration != nil:
+			volume.Docker = &ecs.DockerVolume{
+				Driver:        conv.S(v.DockerVolumeConfiguration.Driver),
+				DriverOptions: _aws.StringValueMap(v.DockerVolumeConfiguration.DriverOpts),
+				Scope:         conv.S(v.DockerVolumeConfiguration.Scope),
+				Autoprovision: conv.B(v.DockerVolumeConfiguration.Autoprovision),
+			}
+		case v.EfsVolumeConfiguration != nil:
+			efs := v.EfsVolumeConfiguration
+			volume.EFS = &ecs.EFSVolume{
+				FileSystemID:      conv.S(efs.FileSystemId),
+				RootDirectory:     conv.S(efs.RootDirectory),
+				TransitEncryption: conv.S(efs.TransitEncryption) == _ecs.EFSTransitEncryptionEnabled,
+			}
+			if efs.AuthorizationConfig != nil {
+				volume.EFS.AccessPointID = conv.S(efs.AuthorizationConfig.AccessPointId)
+				volume.EFS.IAM = conv.S(efs.AuthorizationConfig.Iam) == _ecs.EFSAuthorizationConfigIAMEnabled
+			}
+		case v.Host != nil:
+			volume.HostPath = conv.S(v.Host.SourcePath)
+		}
+		before[volume.Name] = volumeString(volume)
+	}
+
+	after := make(map[string]string)
+	for _, volume := range volumes {
+		after[volume.Name] = volumeString(volume)
+	}
+
+	names := []string{}
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []configChange{}
+	for _, name := range names {
+		changes = appendChange(changes, "Volume "+name, before[name], after[name])
+	}
+	return changes
+}
+
+// volumeString formats the volume for plan and status: empty Docker driver and EFS root directory are
+// shown as the ECS defaults ("local" and "/").
+func volumeString(volume *ecs.Volume) string {
+	switch {
+	case volume.Docker != nil:
+		driver := volume.Docker.Driver
+		if driver == "" {
+			driver = "local"
+		}
+		options := []string{}
+		for k, v := range volume.Docker.DriverOptions {
+			options = append(options, k+"="+v)
+		}
+		sort.Strings(options)
+		s := fmt.Sprintf("docker (driver %s, scope %s", driver, volume.Docker.Scope)
+		if len(options) > 0 {
+			s += ", options " + strings.Join(options, ",")
+		}
+		if volume.Docker.Autoprovision {
+			s += ", autoprovision"
+		}
+		return s + ")"
+	case volume.EFS != nil:
+		rootDirectory := volume.EFS.RootDirectory
+		if rootDirectory == "" {
+			rootDirectory = "/"
+		}
+		s := fmt.Sprintf("efs (%s:%s", volume.EFS.FileSystemID, rootDirectory)
+		if volume.EFS.AccessPointID != "" {
+			s += ", access point " + volume.EFS.AccessPointID
+		}
+		if volume.EFS.TransitEncryption {
+			s += ", transit encryption"
+		}
+		if volume.EFS.IAM {
+			s += ", IAM"
+		}
+		return s + ")"
+	case volume.HostPath != "":
+		return fmt.Sprintf("host (%s)", volume.HostPath)
+	default:
+		return "host (scratch)"
+	}
+}
+
+// containerChanges returns the differences between the current container definition (nil if it does
+// not exist yet) and the container definition that deploy would register.
+func containerChanges(current *_ecs.ContainerDefinition, container *ecs.ContainerDefinition) []configChange {
+	if current == nil {
+		current = &_ecs.ContainerDefinition{}
+	}
+
+	changes := []configChange{}
+	changes = appendChange(changes, "Image", conv.S(current.Image), container.Image)
+	changes = appendChange(changes, "CPU", cpuString(conv.I64(current.Cpu)), cpuString(int64(container.CPU)))
+	changes = appendChange(changes, "Memory", memoryString(conv.I64(current.Memory)), memoryString(int64(container.Memory)))
+	memoryReservation := int64(container.MemoryReservation)
+	if memoryReservation >= int64(container.Memory) {
+		memoryReservation = 0 // not registered (see ecs.Client.UpdateTaskDefinition)
+	}
+	changes = appendChange(changes, "Memory Reservation", memoryString(conv.I64(current.MemoryReservation)), memoryString(memoryReservation))
+
+	currentLogDriver := ""
+	if current.LogConfiguration != nil {
+		currentLogDriver = conv.S(current.LogConfiguration.LogDriver)
+	}
+	changes = appendChange(changes, "Logging", currentLogDriver, container.LogDriver)
+
+	currentPorts := []string{}
+	for _, pm := range current.PortMappings {
+		currentPorts = append(currentPorts, fmt.Sprintf("%s:%d:%d", conv.S(pm.Protocol), conv.I64(pm.ContainerPort), conv.I64(pm.HostPort)))
+	}
+	ports := []string{}
+	for _, pm := range container.PortMappings {
+		ports = append(ports, fmt.Sprintf("%s:%d:%d", pm.Protocol, pm.ContainerPort, pm.HostPort))
+	}
+	changes = appendChange(changes, "Ports (protocol:container:host)", strings.Join(currentPorts, " "), strings.Join(ports, " "))
+
+	currentHealthCheck, healthCheck := "", ""
+	if h := current.HealthCheck; h != nil {
+		currentHealthCheck = healthCheckString(_aws.StringValueSlice(h.Command), conv.I64(h.Interval), conv.I64(h.Timeout), conv.I64(h.Retries), conv.I64(h.StartPeriod))
+	}
+	if h := container.HealthCheck; h != nil {
+		healthCheck = healthCheckString(h.Command, int64(h.Interval), int64(h.Timeout), int64(h.Retries), int64(h.StartPeriod))
+	}
+	changes = appendChange(changes, "Health Check", currentHealthCheck, healthCheck)
+
+	currentMounts := []string{}
+	for _, mp := range current.MountPoints {
+		currentMounts = append(currentMounts, mountString(conv.S(mp.SourceVolume), conv.S(mp.ContainerPath), conv.B(mp.ReadOnly)))
+	}
+	mounts := []string{}
+	for _, mp := range container.MountPoints {
+		mounts = append(mounts, mountString(mp.SourceVolume, mp.ContainerPath, mp.ReadOnly))
+	}
+	changes = appendChange(changes, "Mounts (volume:path)", strings.Join(currentMounts, " "), strings.Join(mounts, " "))
+
+	changes = append(changes, runtimeChanges(current, container)...)
+
+	currentEnvs := make(map[string]string)
+	for _, kv := range current.Environment {
+		currentEnvs[conv.S(kv.Name)] = conv.S(kv.Value)
+	}
+	changes = append(changes, envChanges(currentEnvs, container.Envs)...)
+
+	currentSecrets := make(map[string]string)
+	for _, s := range current.Secrets {
+		currentSecrets[conv.S(s.Name)] = conv.S(s.ValueFrom)
+	}
+	for _, change := range envChanges(currentSecrets, container.Secrets) {
+		change.name = "Secret" + strings.TrimPrefix(change.name, "Env")
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// runtimeChanges returns the differences of the runtime options: lists are compared as a whole.
+func runtimeChanges(current *_ecs.ContainerDefinition, container *ecs.ContainerDefinition) []configChange {
+	changes := []configChange{}
+	changes = appendChange(changes, "Command", commandString(_aws.StringValueSlice(current.Command)), commandString(container.Command))
+	changes = appendChange(changes, "Entrypoint", commandString(_aws.StringValueSlice(current.EntryPoint)), commandString(container.EntryPoint))
+	changes = appendChange(changes, "Working Directory", conv.S(current.WorkingDirectory), container.WorkingDirectory)
+	changes = appendChange(changes, "User", conv.S(current.User), container.User)
+
+	currentUlimits := []string{}
+	for _, u := range current.Ulimits {
+		currentUlimits = append(currentUlimits, fmt.Sprintf("%s=%d:%d", conv.S(u.Name), conv.I64(u.SoftLimit), conv.I64(u.HardLimit)))
+	}
+	ulimits := []string{}
+	for _, u := range container.Ulimits {
+		ulimits = append(ulimits, fmt.Sprintf("%s=%d:%d", u.Name, u.SoftLimit, u.HardLimit))
+	}
+	changes = appendChange(changes, "Ulimits (name=soft:hard)", strings.Join(currentUlimits, " "), strings.Join(ulimits, " "))
+
+	changes = appendChange(changes, "Docker Labels", mapString(_aws.StringValueMap(current.DockerLabels)), mapString(container.DockerLabels))
+
+	currentExtraHosts := make(map[string]string)
+	for _, h := range current.ExtraHosts {
+		currentExtraHosts[conv.S(h.Hostname)] = conv.S(h.IpAddress)
+	}
+	changes = appendChange(changes, "Extra Hosts", mapString(currentExtraHosts), mapString(container.ExtraHosts))
+
+	changes = appendChange(changes, "DNS Servers", strings.Join(_aws.StringValueSlice(current.DnsServers), " "), strings.Join(container.DNSServers, " "))
+
+	currentStopTimeout, stopTimeout := "", ""
+	if conv.I64(current.StopTimeout) > 0 {
+		currentStopTimeout = fmt.Sprintf("%ds", conv.I64(current.StopTimeout))
+	}
+	if container.StopTimeout > 0 {
+		stopTimeout = fmt.Sprintf("%ds", container.StopTimeout)
+	}
+	changes = appendChange(changes, "Stop Timeout", currentStopTimeout, stopTimeout)
+
+	return changes
+}
+
+func commandString(command []string) string {
+	if len(command) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%q", command)
+}
+
+// mapString formats the map as "k1=v1 k2=v2" sorted by key.
+func mapString(m map[string]string) string {
+	pairs := []string{}
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+func mountString(volume, path string, readOnly bool) string {
+	if readOnly {
+		return fmt.Sprintf("%s:%s:ro", volume, path)
+	}
+	return fmt.Sprintf("%s:%s", volume, path)
+}
+
+func healthCheckString(command []string, interval, timeout, retries, startPeriod int64) string {
+	return fmt.Sprintf("%q (interval %ds, timeout %ds, retries %d, start period %ds)",
+		strings.Join(command, " "), interval, timeout, retries, startPeriod)
+}
+
+func cpuString(cpu int64) string {
+	if cpu == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", float64(cpu)/1024.0)
+}
+
+func memoryString(memory int64) string {
+	if memory == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%dm", memory)
+}
+
+func (c *Command) planELBLoadBalancer() error {
+	elbLoadBalancerName := conv.S(c.conf.AWS.ELBLoadBalancerName)
+	elbTargetGroupName := conv.S(c.conf.AWS.ELBTargetGroupName)
+	elbSecurityGroupName := conv.S(c.conf.AWS.ELBSecurityGroupName)
+
+	elbLoadBalancer, err := c.awsClient.ELB().RetrieveLoadBalancerByName(elbLoadBalancerName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve ELB Load Balancer [%s]: %s", elbLoadBalancerName, err.Error())
+	}
+	elbTargetGroup, err := c.awsClient.ELB().RetrieveTargetGroupByName(elbTargetGroupName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve ELB Target Group [%s]: %s", elbTargetGroupName, err.Error())
+	}
+
+	if elbLoadBalancer == nil {
+		securityGroup, err := c.awsClient.EC2().RetrieveSecurityGroupByNameOrID(elbSecurityGroupName)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve EC2 Security Group [%s]: %s", elbSecurityGroupName, err.Error())
+		}
+		if securityGroup == nil {
+			console.PlanAddResource("EC2 Security Group", elbSecurityGroupName)
+		} else {
+			console.PlanNoChangeResource("EC2 Security Group", elbSecurityGroupName)
+		}
+
+		console.PlanAddResource("ELB Target Group", elbTargetGroupName)
+		console.PlanAddResource("ELB Load Balancer", elbLoadBalancerName)
+		c.planELBListeners(elbLoadBalancerName)
+		return nil
+	}
+
+	console.PlanNoChangeResource("ELB Load Balancer", elbLoadBalancerName)
+
+	if elbTargetGroup == nil {
+		console.PlanAddResource("ELB Target Group", elbTargetGroupName)
+		c.planELBListeners(elbLoadBalancerName)
+		return nil
+	}
+
+	listeners, err := c.awsClient.ELB().RetrieveLoadBalancerListeners(conv.S(elbLoadBalancer.LoadBalancerArn))
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve listeners for ELB Load Balancer [%s]: %s", elbLoadBalancerName, err.Error())
+	}
+	for _, l := range listeners {
+		for _, a := range l.DefaultActions {
+			if conv.S(a.TargetGroupArn) == conv.S(elbTargetGroup.TargetGroupArn) {
+				console.PlanNoChangeResource("ELB Target Group", elbTargetGroupName)
+				return nil
+			}
+		}
+	}
+
+	console.PlanNoChangeResource("ELB Target Group", elbTargetGroupName)
+	console.DetailWithResourceNote("Listener", elbLoadBalancerName, "(not found: deploy will fail)", true)
+	return nil
+}
+
+func (c *Command) planELBListeners(elbLoadBalancerName string) {
+	if port := conv.U16(c.conf.LoadBalancer.Port); port > 0 {
+		console.PlanAddResource(fmt.Sprintf("ELB Listener (HTTP:%d)", port), elbLoadBalancerName)
+	}
+	if httpsPort := conv.U16(c.conf.LoadBalancer.HTTPSPort); httpsPort > 0 {
+		console.PlanAddResource(fmt.Sprintf("ELB Listener (HTTPS:%d)", httpsPort), elbLoadBalancerName)
+	}
+}
+
+func (c *Command) planELBTargetGroupHealthCheck(elbTargetGroupARN string) error {
+	elbTargetGroup, err := c.awsClient.ELB().RetrieveTargetGroup(elbTargetGroupARN)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve ELB Target Group [%s]: %s", elbTargetGroupARN, err.Error())
+	}
+	if elbTargetGroup == nil {
+		return fmt.Errorf("ELB Target Group [%s] was not found.", elbTargetGroupARN)
+	}
+
+	checkInterval, err := core.ParseTimeExpression(conv.S(c.conf.LoadBalancer.HealthCheck.Interval))
+	if err != nil {
+		return err
+	}
+	timeout, err := core.ParseTimeExpression(conv.S(c.conf.LoadBalancer.HealthCheck.Timeout))
+	if err != nil {
+		return err
+	}
+
+	changes := c.healthCheckChanges(elbTargetGroup, checkInterval, timeout)
+	if len(changes) == 0 {
+		console.PlanNoChangeResource("ELB Target Group health check", conv.S(elbTargetGroup.TargetGroupName))
+		return nil
+	}
+
+	console.PlanUpdateResource("ELB Target Group health check", conv.S(elbTargetGroup.TargetGroupName))
+	printChanges(changes)
+
+	return nil
+}
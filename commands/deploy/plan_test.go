@@ -0,0 +1,231 @@
+package deploy
+
+import (
+	"testing"
+
+	_aws "github.com/aws/aws-sdk-go/aws"
+	_aas "github.com/aws/aws-sdk-go/service/applicationautoscaling"
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/aws/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/config"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvChanges(t *testing.T) {
+	assert.Empty(t, envChanges(nil, nil))
+	assert.Empty(t, envChanges(map[string]string{"A": "1"}, map[string]string{"A": "1"}))
+
+	changes := envChanges(
+		map[string]string{"A": "1", "B": "2", "C": "3"},
+		map[string]string{"A": "1", "B": "20", "D": ""})
+	assert.Equal(t, []configChange{
+		{name: "Env B", before: `"2"`, after: `"20"`},
+		{name: "Env C", before: `"3"`, after: ""},
+		{name: "Env D", before: "", after: `""`},
+	}, changes)
+}
+
+func TestCommand_DeploymentConfigurationChanges(t *testing.T) {
+	c := &Command{conf: config.DefaultConfig("app1")}
+
+	current := &_ecs.DeploymentConfiguration{
+		MaximumPercent:        _aws.Int64(200),
+		MinimumHealthyPercent: _aws.Int64(50),
+	}
+	assert.Empty(t, c.deploymentConfigurationChanges(current))
+
+	c.conf.Deployment.MinHealthyPercent = conv.U16P(100)
+	c.conf.Deployment.CircuitBreaker.Enabled = conv.BP(true)
+	assert.Equal(t, []configChange{
+		{name: "Min Healthy Percent", before: "50%", after: "100%"},
+		{name: "Circuit Breaker", before: "false", after: "true"},
+	}, c.deploymentConfigurationChanges(current))
+}
+
+func TestCommand_AutoScalingChanges(t *testing.T) {
+	c := &Command{conf: config.DefaultConfig("app1")}
+	c.conf.AutoScaling.Enabled = conv.BP(true)
+	c.conf.AutoScaling.CPUTarget = conv.U16P(70)
+
+	// not registered yet
+	assert.Equal(t, []configChange{
+		{name: "Min Units", before: "", after: "1"},
+		{name: "Max Units", before: "", after: "4"},
+		{name: "CPU Target", before: "", after: "70"},
+	}, c.autoScalingChanges(nil, nil))
+
+	current := &_aas.ScalableTarget{MinCapacity: _aws.Int64(1), MaxCapacity: _aws.Int64(4)}
+	policies := []*_aas.ScalingPolicy{
+		{
+			PolicyName: _aws.String("coldbrew-cpu-target"),
+			TargetTrackingScalingPolicyConfiguration: &_aas.TargetTrackingScalingPolicyConfiguration{
+				TargetValue: _aws.Float64(70),
+			},
+		},
+		{
+			PolicyName: _aws.String("coldbrew-memory-target"),
+			TargetTrackingScalingPolicyConfiguration: &_aas.TargetTrackingScalingPolicyConfiguration{
+				TargetValue: _aws.Float64(80),
+			},
+		},
+	}
+	assert.Equal(t, []configChange{
+		{name: "Memory Target", before: "80", after: ""},
+	}, c.autoScalingChanges(current, policies))
+
+	c.conf.AutoScaling.MemoryTarget = conv.U16P(80)
+	assert.Empty(t, c.autoScalingChanges(current, policies))
+}
+
+func TestPlacementChanges(t *testing.T) {
+	current := &_ecs.Service{
+		PlacementStrategy: []*_ecs.PlacementStrategy{
+			{Type: _aws.String("spread"), Field: _aws.String("instanceId")},
+		},
+	}
+
+	assert.Empty(t, placementChanges(current, &ecs.Placement{
+		Strategies: []ecs.PlacementStrategy{{Type: "spread", Field: "instanceId"}},
+	}))
+
+	assert.Equal(t, []configChange{
+		{name: "Placement Strategy", before: "spread(instanceId)", after: "spread(instanceId), binpack(memory)"},
+		{name: "Placement Constraints", before: "", after: "distinctInstance"},
+	}, placementChanges(current, &ecs.Placement{
+		Strategies: []ecs.PlacementStrategy{
+			{Type: "spread", Field: "instanceId"},
+			{Type: "binpack", Field: "memory"},
+		},
+		Constraints: []ecs.PlacementConstraint{{Type: "distinctInstance"}},
+	}))
+}
+
+func TestECSHealthCheck(t *testing.T) {
+	healthCheck, err := ecsHealthCheck(&config.ConfigHealthCheck{})
+	assert.Nil(t, err)
+	assert.Nil(t, healthCheck)
+
+	healthCheck, err = ecsHealthCheck(&config.ConfigHealthCheck{
+		Command:     []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"},
+		Interval:    conv.SP("1m"),
+		Timeout:     conv.SP("5s"),
+		Retries:     conv.U16P(3),
+		StartPeriod: conv.SP("0"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &ecs.HealthCheck{
+		Command:     []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"},
+		Interval:    60,
+		Timeout:     5,
+		Retries:     3,
+		StartPeriod: 0,
+	}, healthCheck)
+
+	_, err = ecsHealthCheck(&config.ConfigHealthCheck{Command: []string{"CMD", "true"}, Interval: conv.SP("1d")})
+	assert.NotNil(t, err)
+}
+
+func TestVolumeChanges(t *testing.T) {
+	current := []*_ecs.Volume{
+		{Name: _aws.String("scratch"), Host: &_ecs.HostVolumeProperties{}},
+		{Name: _aws.String("data"), EfsVolumeConfiguration: &_ecs.EFSVolumeConfiguration{
+			FileSystemId:      _aws.String("fs-12345678"),
+			RootDirectory:     _aws.String("/"),
+			TransitEncryption: _aws.String(_ecs.EFSTransitEncryptionDisabled),
+		}},
+	}
+
+	assert.Empty(t, volumeChanges(current, []*ecs.Volume{
+		{Name: "scratch"},
+		{Name: "data", EFS: &ecs.EFSVolume{FileSystemID: "fs-12345678"}},
+	}))
+
+	assert.Equal(t, []configChange{
+		{name: "Volume cache", before: "", after: "docker (driver local, scope task)"},
+		{name: "Volume data", before: "efs (fs-12345678:/)", after: ""},
+		{name: "Volume scratch", before: "host (scratch)", after: "host (/var/scratch)"},
+	}, volumeChanges(current, []*ecs.Volume{
+		{Name: "scratch", HostPath: "/var/scratch"},
+		{Name: "cache", Docker: &ecs.DockerVolume{Scope: "task"}},
+	}))
+}
+
+func TestCommand_ECSTaskRoleChanges(t *testing.T) {
+	c := &Command{conf: config.DefaultConfig("app1")}
+
+	changes, err := c.ecsTaskRoleChanges(nil, "")
+	assert.Nil(t, err)
+	assert.Empty(t, changes)
+
+	c.conf.IAM.ManagedPolicies = []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"}
+	c.conf.IAM.Statements = []*config.ConfigIAMStatement{
+		{Effect: conv.SP("Allow"), Actions: []string{"sqs:SendMessage"}, Resources: []string{"*"}},
+	}
+	policyDocument, err := c.ecsTaskRolePolicyDocument()
+	assert.Nil(t, err)
+	changes, err = c.ecsTaskRoleChanges([]string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"}, policyDocument)
+	assert.Nil(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = c.ecsTaskRoleChanges([]string{"arn:aws:iam::aws:policy/AdministratorAccess"}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []configChange{
+		{name: "Managed Policy", before: "", after: "arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"},
+		{name: "Managed Policy", before: "arn:aws:iam::aws:policy/AdministratorAccess", after: ""},
+		{name: "Policy Statements", before: "", after: "Allow sqs:SendMessage on *"},
+	}, changes)
+}
+
+func TestRuntimeChanges(t *testing.T) {
+	current := &_ecs.ContainerDefinition{
+		Command:     _aws.StringSlice([]string{"./echo"}),
+		ExtraHosts:  []*_ecs.HostEntry{{Hostname: _aws.String("db.internal"), IpAddress: _aws.String("10.0.0.10")}},
+		StopTimeout: _aws.Int64(30),
+	}
+
+	assert.Empty(t, runtimeChanges(current, &ecs.ContainerDefinition{
+		Command:     []string{"./echo"},
+		ExtraHosts:  map[string]string{"db.internal": "10.0.0.10"},
+		StopTimeout: 30,
+	}))
+
+	assert.Equal(t, []configChange{
+		{name: "Command", before: `["./echo"]`, after: `["./echo" "--verbose"]`},
+		{name: "User", before: "", after: "nobody"},
+		{name: "Ulimits (name=soft:hard)", before: "", after: "nofile=1024:4096"},
+		{name: "Extra Hosts", before: "db.internal=10.0.0.10", after: ""},
+		{name: "Stop Timeout", before: "30s", after: ""},
+	}, runtimeChanges(current, &ecs.ContainerDefinition{
+		Command: []string{"./echo", "--verbose"},
+		User:    "nobody",
+		Ulimits: []ecs.Ulimit{{Name: "nofile", SoftLimit: 1024, HardLimit: 4096}},
+	}))
+}
+
+func TestScheduleChanges(t *testing.T) {
+	schedule := &config.ConfigSchedule{
+		Name:       conv.SP("nightly"),
+		Expression: conv.SP("cron(0 3 * * ? *)"),
+		Command:    []string{"./report"},
+		Count:      conv.U16P(1),
+	}
+
+	input := `{"containerOverrides":[{"name":"echo","command":["./report"]}]}`
+	assert.Equal(t, []string{"./report"}, targetCommand(input, "echo"))
+	assert.Nil(t, targetCommand(input, "proxy"))
+	assert.Nil(t, targetCommand("", "echo"))
+
+	assert.Empty(t, scheduleChanges("cron(0 3 * * ? *)", _aws.Int64(1), targetCommand(input, "echo"), schedule))
+
+	assert.Equal(t, []configChange{
+		{name: "Expression", before: "", after: "cron(0 3 * * ? *)"},
+		{name: "Task Count", before: "", after: "1"},
+		{name: "Command", before: "", after: `["./report"]`},
+	}, scheduleChanges("", nil, nil, schedule))
+
+	assert.Equal(t, []configChange{
+		{name: "Expression", before: "rate(1 day)", after: "cron(0 3 * * ? *)"},
+		{name: "Task Count", before: "2", after: "1"},
+	}, scheduleChanges("rate(1 day)", _aws.Int64(2), []string{"./report"}, schedule))
+}
@@ -0,0 +1,32 @@
+package deploy
+
+import (
+	"fmt"
+
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+)
+
+// rollbackECSService points the ECS Service back to the ECS Task Definition it was running before
+// the failed deployment. It always returns an error that describes what was rolled back and why.
+func (c *Command) rollbackECSService(ecsClusterName, ecsServiceName, previousTaskDefinitionARN, failedTaskDefinitionARN string, reason error) error {
+	previousRevision := aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(previousTaskDefinitionARN)
+	failedRevision := aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(failedTaskDefinitionARN)
+
+	console.Error(fmt.Sprintf("Deployment of ECS Task Definition [%s] failed: %s", failedRevision, reason.Error()))
+	console.UpdatingResource("Rolling back ECS Service", fmt.Sprintf("%s -> %s", failedRevision, previousRevision), false)
+	_, err := c.awsClient.ECS().UpdateService(ecsClusterName, ecsServiceName, previousTaskDefinitionARN, c.ecsDesiredCount(), nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to roll back ECS Service [%s] to ECS Task Definition [%s]: %s (deployment failure: %s)",
+			ecsServiceName, previousRevision, err.Error(), reason.Error())
+	}
+
+	// best effort: report if the rollback itself did not settle in time
+	if err := c.waitForDeployment(ecsClusterName, ecsServiceName, previousTaskDefinitionARN); err != nil {
+		return fmt.Errorf("Rolled back ECS Service [%s] from [%s] to [%s] because the deployment failed (%s), but the rollback did not become stable: %s",
+			ecsServiceName, failedRevision, previousRevision, reason.Error(), err.Error())
+	}
+
+	return fmt.Errorf("Rolled back ECS Service [%s] from [%s] to [%s] because the deployment failed: %s",
+		ecsServiceName, failedRevision, previousRevision, reason.Error())
+}
@@ -0,0 +1,285 @@
+package deploy
+
+import (
+	"fmt"
+	"time"
+
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/utils"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+const waitPollInterval = 5 * time.Second
+
+// circuitBreakerError is returned when the ECS deployment circuit breaker failed the deployment. Unlike the
+// failures detected by coldbrew-cli's own checks, ECS rolls these back by itself if rollback is enabled.
+type circuitBreakerError struct {
+	message string
+}
+
+func (e *circuitBreakerError) Error() string {
+	return e.message
+}
+
+// waitForDeployment waits until the deployment of the ECS Task Definition to the ECS Service becomes stable, all of
+// its tasks pass the container health checks (if any), and, if the service is behind a load balancer, all of its
+// ELB targets pass the health check.
+func (c *Command) waitForDeployment(ecsClusterName, ecsServiceName, ecsTaskDefinitionARN string) error {
+	timeoutSeconds, err := core.ParseTimeExpression(conv.S(c._commandFlags.WaitTimeout))
+	if err != nil {
+		return err
+	}
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	deadline := time.Now().Add(timeout)
+
+	ecsService, err := c.waitForECSServiceStable(ecsClusterName, ecsServiceName, ecsTaskDefinitionARN, timeout, deadline)
+	if err != nil {
+		return err
+	}
+
+	if err := c.waitForTasksHealthy(ecsClusterName, ecsService, timeout, deadline); err != nil {
+		return err
+	}
+
+	for _, lb := range ecsService.LoadBalancers {
+		if err := c.waitForELBTargetsHealthy(conv.S(lb.TargetGroupArn), conv.I64(ecsService.DesiredCount), timeout, deadline); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// waitForECSServiceStable waits until the PRIMARY deployment of the ECS Service runs the ECS Task Definition and
+// becomes stable. The service may still be described as it was before the update for a while: until the deployment
+// of the task definition shows up, the service is not considered stable.
+func (c *Command) waitForECSServiceStable(ecsClusterName, ecsServiceName, ecsTaskDefinitionARN string, timeout time.Duration, deadline time.Time) (*_ecs.Service, error) {
+	console.ProcessingOnResource("Waiting for ECS Service to become stable", ecsServiceName, true)
+
+	lastProgress := ""
+	primaryDeploymentID := "" // deployment being waited for
+	for {
+		ecsService, err := c.awsClient.ECS().RetrieveService(ecsClusterName, ecsServiceName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve ECS Service [%s/%s]: %s", ecsClusterName, ecsServiceName, err.Error())
+		}
+		if ecsService == nil || conv.S(ecsService.Status) != "ACTIVE" {
+			return nil, fmt.Errorf("ECS Service [%s/%s] is not active.", ecsClusterName, ecsServiceName)
+		}
+
+		for _, d := range ecsService.Deployments {
+			if primaryDeploymentID == "" && conv.S(d.Status) == "PRIMARY" && conv.S(d.TaskDefinition) == ecsTaskDefinitionARN {
+				primaryDeploymentID = conv.S(d.Id)
+			}
+		}
+
+		// ECS deployment circuit breaker: the deployment fails, and gets replaced by a rollback deployment
+		// if rollback is enabled
+		for _, d := range ecsService.Deployments {
+			if conv.S(d.Id) == primaryDeploymentID && conv.S(d.RolloutState) == _ecs.DeploymentRolloutStateFailed {
+				return nil, &circuitBreakerError{fmt.Sprintf("ECS deployment circuit breaker stopped the deployment of [%s]: %s",
+					aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(conv.S(d.TaskDefinition)), conv.S(d.RolloutStateReason))}
+			}
+		}
+		for _, d := range ecsService.Deployments {
+			if primaryDeploymentID != "" && conv.S(d.Status) == "PRIMARY" && conv.S(d.Id) != primaryDeploymentID {
+				return nil, fmt.Errorf("ECS Service [%s/%s] started another deployment of [%s] before this deployment completed.",
+					ecsClusterName, ecsServiceName, aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(conv.S(d.TaskDefinition)))
+			}
+		}
+
+		// print progress only when it changes
+		progress := deploymentsProgress(ecsService)
+		if progress != lastProgress {
+			console.DetailWithResource("Deployments", progress)
+			lastProgress = progress
+		}
+
+		if primaryDeploymentID != "" && isECSServiceStable(ecsService) {
+			return ecsService, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("ECS Service [%s/%s] did not become stable within %s.", ecsClusterName, ecsServiceName, timeout.String())
+		}
+
+		time.Sleep(waitPollInterval)
+	}
+}
+
+// waitForTasksHealthy waits until the running tasks of the ECS Task Definition that the ECS Service is deployed
+// with pass their container health checks. It returns immediately if none of the containers has a health check.
+// Health of each container with a health check is checked, because ECS rolls up only the essential containers
+// into the task health status.
+func (c *Command) waitForTasksHealthy(ecsClusterName string, ecsService *_ecs.Service, timeout time.Duration, deadline time.Time) error {
+	ecsServiceName := conv.S(ecsService.ServiceName)
+	ecsTaskDefinitionARN := conv.S(ecsService.TaskDefinition)
+	ecsTaskDefinition, err := c.awsClient.ECS().RetrieveTaskDefinition(ecsTaskDefinitionARN)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve ECS Task Definition [%s]: %s", ecsTaskDefinitionARN, err.Error())
+	}
+	healthCheckedContainers := []string{}
+	for _, cd := range ecsTaskDefinition.ContainerDefinitions {
+		if cd.HealthCheck != nil {
+			healthCheckedContainers = append(healthCheckedContainers, conv.S(cd.Name))
+		}
+	}
+	if len(healthCheckedContainers) == 0 {
+		return nil
+	}
+
+	console.ProcessingOnResource("Waiting for ECS Tasks to pass container health checks", ecsServiceName, true)
+
+	desiredCount := conv.I64(ecsService.DesiredCount)
+	lastProgress := ""
+	for {
+		taskARNs, err := c.awsClient.ECS().ListServiceTaskARNs(ecsClusterName, ecsServiceName)
+		if err != nil {
+			return fmt.Errorf("Failed to list ECS Tasks of ECS Service [%s]: %s", ecsServiceName, err.Error())
+		}
+		tasks, err := c.awsClient.ECS().RetrieveTasks(ecsClusterName, taskARNs)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve ECS Tasks of ECS Service [%s]: %s", ecsServiceName, err.Error())
+		}
+
+		healthy, lastReason := 0, ""
+		for _, task := range tasks {
+			if conv.S(task.TaskDefinitionArn) != ecsTaskDefinitionARN || conv.S(task.LastStatus) != "RUNNING" {
+				continue
+			}
+			status, unhealthyContainer := taskContainersHealth(task, healthCheckedContainers)
+			switch status {
+			case _ecs.HealthStatusHealthy:
+				healthy++
+			case _ecs.HealthStatusUnhealthy:
+				lastReason = fmt.Sprintf("container [%s] is unhealthy", unhealthyContainer)
+			}
+		}
+
+		progress := fmt.Sprintf("%d/%d (healthy/desired)", healthy, desiredCount)
+		if progress != lastProgress {
+			console.DetailWithResource("ECS Tasks", progress)
+			lastProgress = progress
+		}
+
+		if int64(healthy) >= desiredCount {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if lastReason != "" {
+				return fmt.Errorf("ECS Tasks did not pass container health checks within %s: %s", timeout.String(), lastReason)
+			}
+			return fmt.Errorf("ECS Tasks did not pass container health checks within %s.", timeout.String())
+		}
+
+		time.Sleep(waitPollInterval)
+	}
+}
+
+// taskContainersHealth returns HEALTHY if all the containers are healthy, UNHEALTHY (and the name of the unhealthy
+// container) if any of them is unhealthy, or UNKNOWN otherwise.
+func taskContainersHealth(task *_ecs.Task, containerNames []string) (string, string) {
+	statuses := make(map[string]string)
+	for _, container := range task.Containers {
+		statuses[conv.S(container.Name)] = conv.S(container.HealthStatus)
+	}
+
+	status := _ecs.HealthStatusHealthy
+	for _, name := range containerNames {
+		switch statuses[name] {
+		case _ecs.HealthStatusHealthy:
+		case _ecs.HealthStatusUnhealthy:
+			return _ecs.HealthStatusUnhealthy, name
+		default:
+			status = _ecs.HealthStatusUnknown
+		}
+	}
+	return status, ""
+}
+
+func (c *Command) waitForELBTargetsHealthy(elbTargetGroupARN string, desiredCount int64, timeout time.Duration, deadline time.Time) error {
+	console.ProcessingOnResource("Waiting for ELB Targets to become healthy", elbTargetGroupARN, true)
+
+	lastProgress := ""
+	for {
+		targets, err := c.awsClient.ELB().RetrieveTargetHealth(elbTargetGroupARN)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve target health of ELB Target Group [%s]: %s", elbTargetGroupARN, err.Error())
+		}
+
+		healthy, unhealthy, lastReason := 0, 0, ""
+		for _, t := range targets {
+			if t.TargetHealth == nil {
+				continue
+			}
+			switch conv.S(t.TargetHealth.State) {
+			case "healthy":
+				healthy++
+			case "draining":
+				// targets of previous tasks: ignored
+			default:
+				unhealthy++
+				if !utils.IsBlank(conv.S(t.TargetHealth.Description)) {
+					lastReason = conv.S(t.TargetHealth.Description)
+				}
+			}
+		}
+
+		progress := fmt.Sprintf("%d/%d (healthy/desired)", healthy, desiredCount)
+		if progress != lastProgress {
+			console.DetailWithResource("ELB Targets", progress)
+			lastProgress = progress
+		}
+
+		if int64(healthy) >= desiredCount && unhealthy == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if lastReason != "" {
+				return fmt.Errorf("ELB Targets did not become healthy within %s: %s", timeout.String(), lastReason)
+			}
+			return fmt.Errorf("ELB Targets did not become healthy within %s.", timeout.String())
+		}
+
+		time.Sleep(waitPollInterval)
+	}
+}
+
+// isECSServiceStable returns true if the PRIMARY deployment is fully running and
+// there are no ACTIVE (previous) deployments left.
+func isECSServiceStable(ecsService *_ecs.Service) bool {
+	primaryStable := false
+	for _, d := range ecsService.Deployments {
+		switch conv.S(d.Status) {
+		case "PRIMARY":
+			primaryStable = conv.I64(d.RunningCount) == conv.I64(d.DesiredCount)
+		case "ACTIVE":
+			return false
+		}
+	}
+	return primaryStable
+}
+
+func deploymentsProgress(ecsService *_ecs.Service) string {
+	progress := ""
+	for _, d := range ecsService.Deployments {
+		if progress != "" {
+			progress += ", "
+		}
+		status := conv.S(d.Status)
+		if !utils.IsBlank(conv.S(d.RolloutState)) {
+			status = fmt.Sprintf("%s:%s", status, conv.S(d.RolloutState))
+		}
+		progress += fmt.Sprintf("%s %d/%d/%d (running/desired/pending)",
+			status,
+			conv.I64(d.RunningCount),
+			conv.I64(d.DesiredCount),
+			conv.I64(d.PendingCount))
+	}
+	return progress
+}
@@ -0,0 +1,35 @@
+package deploy
+
+import (
+	"testing"
+
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskContainersHealth(t *testing.T) {
+	// task health status is UNKNOWN when only a non-essential container has a health check
+	task := &_ecs.Task{
+		HealthStatus: conv.SP(_ecs.HealthStatusUnknown),
+		Containers: []*_ecs.Container{
+			{Name: conv.SP("app1"), HealthStatus: conv.SP(_ecs.HealthStatusUnknown)},
+			{Name: conv.SP("proxy"), HealthStatus: conv.SP(_ecs.HealthStatusHealthy)},
+		},
+	}
+	status, unhealthy := taskContainersHealth(task, []string{"proxy"})
+	assert.Equal(t, _ecs.HealthStatusHealthy, status)
+	assert.Empty(t, unhealthy)
+
+	status, _ = taskContainersHealth(task, []string{"app1", "proxy"})
+	assert.Equal(t, _ecs.HealthStatusUnknown, status)
+
+	task.Containers[0].HealthStatus = conv.SP(_ecs.HealthStatusUnhealthy)
+	status, unhealthy = taskContainersHealth(task, []string{"app1", "proxy"})
+	assert.Equal(t, _ecs.HealthStatusUnhealthy, status)
+	assert.Equal(t, "app1", unhealthy)
+
+	// container not reported yet
+	status, _ = taskContainersHealth(task, []string{"sidecar"})
+	assert.Equal(t, _ecs.HealthStatusUnknown, status)
+}
@@ -0,0 +1,187 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/flags"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	watchPollInterval = 5 * time.Second
+	timeFormat        = "2006-01-02 15:04:05"
+)
+
+type Command struct {
+	globalFlags  *flags.GlobalFlags
+	commandFlags *Flags
+	awsClient    *aws.Client
+}
+
+// event is an entry in the timeline: an ECS Service event or a stopped ECS Task.
+type event struct {
+	key            string // unique across services and tasks
+	time           time.Time
+	ecsServiceName string
+	lines          []string
+}
+
+func (c *Command) Init(ka *kingpin.Application, globalFlags *flags.GlobalFlags) *kingpin.CmdClause {
+	c.globalFlags = globalFlags
+
+	cmd := ka.Command("events",
+		"See: "+console.ColorFnHelpLink("https://github.com/coldbrewcloud/coldbrew-cli/wiki/CLI-Command:-events"))
+	c.commandFlags = NewFlags(cmd)
+
+	return cmd
+}
+
+func (c *Command) Run() error {
+	c.awsClient = c.globalFlags.GetAWSClient()
+
+	// app configuration
+	conf, appName, clusterName, err := commands.ResolveApp(c.globalFlags, c.commandFlags.AppName, c.commandFlags.ClusterName)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+
+	sinceSeconds, err := core.ParseTimeExpression(conv.S(c.commandFlags.Since))
+	if err != nil {
+		return console.ExitWithErrorString("Invalid since [%s]", conv.S(c.commandFlags.Since))
+	}
+
+	// test if target cluster is available to use
+	console.ProcessingOnResource("Checking cluster availability", clusterName, false)
+	if err := commands.CheckClusterAvailability(c.awsClient, clusterName); err != nil {
+		return console.ExitWithError(core.NewErrorExtraInfo(err, "https://github.com/coldbrewcloud/coldbrew-cli/wiki/Error:-Cluster-not-found"))
+	}
+
+	ecsClusterName := core.DefaultECSClusterName(clusterName)
+
+	// blue/green: events of both ECS Services (live first)
+	ecsServiceNames, err := commands.AppECSServiceNames(c.awsClient, conf, appName, clusterName)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+
+	since := time.Now().Add(-time.Duration(sinceSeconds) * time.Second)
+	printed := map[string]bool{}
+	for {
+		events, err := c.retrieveEvents(ecsClusterName, ecsServiceNames)
+		if err != nil {
+			return console.ExitWithError(err)
+		}
+
+		sort.SliceStable(events, func(i, j int) bool {
+			return events[i].time.Before(events[j].time)
+		})
+
+		for _, e := range events {
+			if printed[e.key] || e.time.Before(since) {
+				continue
+			}
+			printed[e.key] = true
+
+			source := e.time.Local().Format(timeFormat)
+			if len(ecsServiceNames) > 1 {
+				source += " " + e.ecsServiceName
+			}
+			for _, line := range e.lines {
+				console.LogEvent(source, line)
+			}
+		}
+
+		if !conv.B(c.commandFlags.Watch) {
+			return nil
+		}
+
+		time.Sleep(watchPollInterval)
+	}
+}
+
+// retrieveEvents returns the events of the ECS Services and their stopped ECS Tasks. ECS Services that do not
+// exist are skipped, unless none of them exist.
+func (c *Command) retrieveEvents(ecsClusterName string, ecsServiceNames []string) ([]*event, error) {
+	events := []*event{}
+	found := false
+
+	for _, ecsServiceName := range ecsServiceNames {
+		ecsService, err := c.awsClient.ECS().RetrieveService(ecsClusterName, ecsServiceName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve ECS Service [%s/%s]: %s", ecsClusterName, ecsServiceName, err.Error())
+		}
+		if ecsService == nil || conv.S(ecsService.Status) != "ACTIVE" {
+			continue
+		}
+		found = true
+
+		for _, e := range ecsService.Events {
+			if e.CreatedAt == nil {
+				continue
+			}
+			events = append(events, &event{
+				key:            "event:" + conv.S(e.Id),
+				time:           *e.CreatedAt,
+				ecsServiceName: ecsServiceName,
+				lines:          []string{conv.S(e.Message)},
+			})
+		}
+
+		taskARNs, err := c.awsClient.ECS().ListServiceStoppedTaskARNs(ecsClusterName, ecsServiceName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list stopped ECS Tasks for ECS Service [%s]: %s", ecsServiceName, err.Error())
+		}
+		tasks, err := c.awsClient.ECS().RetrieveTasks(ecsClusterName, taskARNs)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve stopped ECS Tasks for ECS Service [%s]: %s", ecsServiceName, err.Error())
+		}
+		for _, task := range tasks {
+			// tasks still stopping show up once they stopped
+			if conv.S(task.LastStatus) != _ecs.DesiredStatusStopped || task.StoppedAt == nil {
+				continue
+			}
+			events = append(events, &event{
+				key:            "task:" + conv.S(task.TaskArn),
+				time:           *task.StoppedAt,
+				ecsServiceName: ecsServiceName,
+				lines:          stoppedTaskLines(task),
+			})
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("ECS Service [%s/%s] not found", ecsClusterName, ecsServiceNames[0])
+	}
+
+	return events, nil
+}
+
+// stoppedTaskLines describes why the ECS Task stopped: the task's stopped reason, followed by the exit code and
+// reason of each container.
+func stoppedTaskLines(task *_ecs.Task) []string {
+	lines := []string{fmt.Sprintf("(task %s) stopped: %s",
+		aws.GetECSTaskIDFromARN(conv.S(task.TaskArn)), conv.S(task.StoppedReason))}
+
+	for _, container := range task.Containers {
+		line := fmt.Sprintf("  container [%s]", conv.S(container.Name))
+		if container.ExitCode != nil {
+			line += fmt.Sprintf(" exited with code %d", conv.I64(container.ExitCode))
+		} else {
+			line += " did not run"
+		}
+		if reason := conv.S(container.Reason); reason != "" {
+			line += ": " + reason
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
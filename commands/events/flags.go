@@ -0,0 +1,19 @@
+package events
+
+import "gopkg.in/alecthomas/kingpin.v2"
+
+type Flags struct {
+	AppName     *string
+	ClusterName *string
+	Since       *string
+	Watch       *bool
+}
+
+func NewFlags(kc *kingpin.CmdClause) *Flags {
+	return &Flags{
+		AppName:     kc.Flag("app-name", "App name").Default("").String(),
+		ClusterName: kc.Flag("cluster-name", "Cluster name").Default("").String(),
+		Since:       kc.Flag("since", "Print events since (e.g. \"30m\", \"2h\")").Default("1h").String(),
+		Watch:       kc.Flag("watch", "Keep printing new events").Short('w').Bool(),
+	}
+}
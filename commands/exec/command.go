@@ -0,0 +1,272 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	_exec "os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/exec"
+	"github.com/coldbrewcloud/coldbrew-cli/flags"
+	"github.com/coldbrewcloud/coldbrew-cli/utils"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const sshBin = "ssh"
+
+// defaultCommand is run in the container if no command is given: interactive shell.
+var defaultCommand = []string{"/bin/sh"}
+
+type Command struct {
+	globalFlags  *flags.GlobalFlags
+	commandFlags *Flags
+	awsClient    *aws.Client
+	commandArg   *[]string
+}
+
+func (c *Command) Init(ka *kingpin.Application, globalFlags *flags.GlobalFlags) *kingpin.CmdClause {
+	c.globalFlags = globalFlags
+
+	cmd := ka.Command("exec",
+		"See: "+console.ColorFnHelpLink("https://github.com/coldbrewcloud/coldbrew-cli/wiki/CLI-Command:-exec"))
+	c.commandFlags = NewFlags(cmd)
+
+	c.commandArg = cmd.Arg("command", "Command to run in the container (default: shell; use \"--\" before the command if it has flags)").Strings()
+
+	return cmd
+}
+
+func (c *Command) Run() error {
+	c.awsClient = c.globalFlags.GetAWSClient()
+
+	// app configuration
+	conf, appName, clusterName, err := commands.ResolveApp(c.globalFlags, c.commandFlags.AppName, c.commandFlags.ClusterName)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+
+	// test if target cluster is available to use
+	console.ProcessingOnResource("Checking cluster availability", clusterName, false)
+	if err := commands.CheckClusterAvailability(c.awsClient, clusterName); err != nil {
+		return console.ExitWithError(core.NewErrorExtraInfo(err, "https://github.com/coldbrewcloud/coldbrew-cli/wiki/Error:-Cluster-not-found"))
+	}
+
+	ecsClusterName := core.DefaultECSClusterName(clusterName)
+
+	// blue/green: use the ECS Service that's live
+	ecsServiceNames, err := commands.AppECSServiceNames(c.awsClient, conf, appName, clusterName)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+	ecsServiceName := ecsServiceNames[0]
+
+	// ECS Task and its container
+	task, err := c.findTask(ecsClusterName, ecsServiceName, strings.TrimSpace(conv.S(c.commandFlags.TaskID)))
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+	taskID := aws.GetECSTaskIDFromARN(conv.S(task.TaskArn))
+
+	containerName := conv.S(c.commandFlags.ContainerName)
+	if utils.IsBlank(containerName) {
+		containerName = core.DefaultECSTaskMainContainerName(appName)
+	}
+	dockerContainerID := ""
+	for _, container := range task.Containers {
+		if conv.S(container.Name) == containerName && conv.S(container.LastStatus) == "RUNNING" {
+			dockerContainerID = conv.S(container.RuntimeId)
+			break
+		}
+	}
+	if dockerContainerID == "" {
+		return console.ExitWithErrorString("Container [%s] is not running in ECS Task [%s]", containerName, taskID)
+	}
+
+	// container instance (EC2 instance) that runs the task
+	containerInstances, err := c.awsClient.ECS().RetrieveContainerInstances(ecsClusterName, []string{conv.S(task.ContainerInstanceArn)})
+	if err != nil {
+		return console.ExitWithErrorString("Failed to retrieve ECS Container Instances: %s", err.Error())
+	}
+	if len(containerInstances) == 0 {
+		return console.ExitWithErrorString("ECS Container Instance of ECS Task [%s] not found", taskID)
+	}
+	ec2InstanceID := conv.S(containerInstances[0].Ec2InstanceId)
+	ec2Instances, err := c.awsClient.EC2().RetrieveInstances([]string{ec2InstanceID})
+	if err != nil {
+		return console.ExitWithErrorString("Failed to retrieve EC2 Instances: %s", err.Error())
+	}
+	if len(ec2Instances) == 0 {
+		return console.ExitWithErrorString("EC2 Instance [%s] not found", ec2InstanceID)
+	}
+
+	// hosts in private subnets can be reached only through the bastion
+	bastion := strings.TrimSpace(conv.S(c.commandFlags.Bastion))
+	host := conv.S(ec2Instances[0].PublicIpAddress)
+	if host == "" || bastion != "" {
+		host = conv.S(ec2Instances[0].PrivateIpAddress)
+	}
+	if host == "" {
+		return console.ExitWithErrorString("EC2 Instance [%s] has no IP address", ec2InstanceID)
+	}
+
+	identityFile, err := c.identityFile(clusterName)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+
+	command := *c.commandArg
+	if len(command) == 0 {
+		command = defaultCommand
+	}
+
+	console.DetailWithResource("ECS Task", taskID)
+	console.DetailWithResource("Container", containerName)
+	console.DetailWithResource("EC2 Instance", ec2InstanceID)
+
+	args := sshArgs(conv.S(c.commandFlags.SSHUser), host, identityFile, bastion, isTerminal(), dockerContainerID, command)
+	console.Blank()
+	console.ShellCommand(sshBin + " " + strings.Join(args, " "))
+	if err := exec.Interactive(sshBin, args...); err != nil {
+		// exit with the exit code of the command (or ssh)
+		if exitErr, ok := err.(*_exec.ExitError); ok {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+				os.Exit(status.ExitStatus())
+			}
+		}
+		return console.ExitWithErrorString("Failed to run [%s]: %s", sshBin, err.Error())
+	}
+
+	return nil
+}
+
+// findTask returns the running ECS Task of the ECS Service with the ID, or any of them if task ID is empty.
+func (c *Command) findTask(ecsClusterName, ecsServiceName, taskID string) (*_ecs.Task, error) {
+	taskARNs, err := c.awsClient.ECS().ListServiceTaskARNs(ecsClusterName, ecsServiceName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list ECS Tasks for ECS Service [%s]: %s", ecsServiceName, err.Error())
+	}
+	tasks, err := c.awsClient.ECS().RetrieveTasks(ecsClusterName, taskARNs)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve ECS Tasks for ECS Service [%s]: %s", ecsServiceName, err.Error())
+	}
+
+	for _, task := range tasks {
+		if conv.S(task.LastStatus) != "RUNNING" {
+			continue
+		}
+		if taskID == "" || aws.GetECSTaskIDFromARN(conv.S(task.TaskArn)) == taskID {
+			return task, nil
+		}
+	}
+
+	if taskID != "" {
+		return nil, fmt.Errorf("ECS Task [%s] is not running in ECS Service [%s/%s]", taskID, ecsClusterName, ecsServiceName)
+	}
+	return nil, fmt.Errorf("No running ECS Tasks in ECS Service [%s/%s]", ecsClusterName, ecsServiceName)
+}
+
+// identityFile returns the SSH private key to use: the flag if set, or "~/.ssh/{key pair}.pem" of the cluster's
+// key pair if it exists. It returns an empty string to let ssh use its own configuration.
+func (c *Command) identityFile(clusterName string) (string, error) {
+	if identityFile := conv.S(c.commandFlags.IdentityFile); !utils.IsBlank(identityFile) {
+		if !utils.FileExists(identityFile) {
+			return "", fmt.Errorf("Identity file [%s] not found", identityFile)
+		}
+		return identityFile, nil
+	}
+
+	launchConfigName := core.DefaultLaunchConfigurationName(clusterName)
+	launchConfig, err := c.awsClient.AutoScaling().RetrieveLaunchConfiguration(launchConfigName)
+	if err != nil {
+		return "", fmt.Errorf("Failed to retrieve Launch Configuration [%s]: %s", launchConfigName, err.Error())
+	}
+	if launchConfig == nil || conv.S(launchConfig.KeyName) == "" {
+		return "", nil
+	}
+
+	keyFile := filepath.Join(os.Getenv("HOME"), ".ssh", conv.S(launchConfig.KeyName)+".pem")
+	if utils.FileExists(keyFile) {
+		return keyFile, nil
+	}
+	console.DetailWithResourceNote("Key Pair", conv.S(launchConfig.KeyName), fmt.Sprintf("(%s not found: use --identity-file)", keyFile), true)
+	return "", nil
+}
+
+// sshArgs returns the ssh arguments to run "docker exec" in the Docker container on the host, optionally through
+// the bastion host (see splitBastion).
+func sshArgs(user, host, identityFile, bastion string, tty bool, dockerContainerID string, command []string) []string {
+	args := []string{}
+	if identityFile != "" {
+		args = append(args, "-i", identityFile)
+	}
+	if bastion != "" {
+		proxyCommand := []string{sshBin}
+		if identityFile != "" {
+			proxyCommand = append(proxyCommand, "-i", shellQuote(identityFile))
+		}
+		destination, port := splitBastion(bastion)
+		if port != "" {
+			proxyCommand = append(proxyCommand, "-p", shellQuote(port))
+		}
+		proxyCommand = append(proxyCommand, "-W", "%h:%p", shellQuote(destination))
+		args = append(args, "-o", "ProxyCommand="+strings.Join(proxyCommand, " "))
+	}
+
+	dockerExec := []string{"docker", "exec", "-i"}
+	if tty {
+		args = append(args, "-t")
+		dockerExec = append(dockerExec, "-t")
+	}
+	dockerExec = append(dockerExec, dockerContainerID)
+	for _, arg := range command {
+		dockerExec = append(dockerExec, shellQuote(arg))
+	}
+
+	return append(args, user+"@"+host, strings.Join(dockerExec, " "))
+}
+
+// splitBastion splits the bastion host ("[user@]host[:port]") into the ssh destination ("[user@]host") and the port.
+// IPv6 addresses with a port must be enclosed in brackets ("[user@][addr]:port").
+func splitBastion(bastion string) (string, string) {
+	user, host := "", bastion
+	if i := strings.LastIndex(bastion, "@"); i >= 0 {
+		user, host = bastion[:i+1], bastion[i+1:]
+	}
+
+	port := ""
+	if strings.HasPrefix(host, "[") {
+		if i := strings.Index(host, "]"); i >= 0 {
+			if strings.HasPrefix(host[i+1:], ":") {
+				port = host[i+2:]
+			}
+			host = host[1:i]
+		}
+	} else if strings.Count(host, ":") == 1 {
+		i := strings.Index(host, ":")
+		host, port = host[:i], host[i+1:]
+	}
+
+	return user + host, port
+}
+
+// shellQuote quotes the argument for the remote shell.
+func shellQuote(arg string) string {
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
+
+// isTerminal returns true if the standard input is a terminal.
+func isTerminal() bool {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,91 @@
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, `'ls'`, shellQuote("ls"))
+	assert.Equal(t, `''`, shellQuote(""))
+	assert.Equal(t, `'a b; rm -rf /'`, shellQuote("a b; rm -rf /"))
+	assert.Equal(t, `'$(id) `+"`id`"+`'`, shellQuote("$(id) `id`"))
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+}
+
+func TestSplitBastion(t *testing.T) {
+	tests := []struct {
+		bastion     string
+		destination string
+		port        string
+	}{
+		{"bastion.example.com", "bastion.example.com", ""},
+		{"bastion.example.com:2222", "bastion.example.com", "2222"},
+		{"ec2-user@bastion.example.com", "ec2-user@bastion.example.com", ""},
+		{"ec2-user@bastion.example.com:2222", "ec2-user@bastion.example.com", "2222"},
+		{"2001:db8::1", "2001:db8::1", ""},
+		{"[2001:db8::1]", "2001:db8::1", ""},
+		{"[2001:db8::1]:2222", "2001:db8::1", "2222"},
+		{"ec2-user@[2001:db8::1]:2222", "ec2-user@2001:db8::1", "2222"},
+	}
+	for _, test := range tests {
+		destination, port := splitBastion(test.bastion)
+		assert.Equal(t, test.destination, destination, test.bastion)
+		assert.Equal(t, test.port, port, test.bastion)
+	}
+}
+
+func TestSSHArgs(t *testing.T) {
+	tests := []struct {
+		identityFile string
+		bastion      string
+		tty          bool
+		command      []string
+		args         []string
+	}{
+		{
+			command: []string{"ls", "-l"},
+			args:    []string{"ec2-user@10.0.0.1", "docker exec -i c1 'ls' '-l'"},
+		},
+		{
+			tty:     true,
+			command: []string{"sh"},
+			args:    []string{"-t", "ec2-user@10.0.0.1", "docker exec -i -t c1 'sh'"},
+		},
+		{
+			command: []string{"sh", "-c", "echo 'hi' && env | grep $HOME"},
+			args:    []string{"ec2-user@10.0.0.1", `docker exec -i c1 'sh' '-c' 'echo '\''hi'\'' && env | grep $HOME'`},
+		},
+		{
+			identityFile: "/keys/my key.pem",
+			bastion:      "bastion.example.com",
+			command:      []string{"ls"},
+			args: []string{
+				"-i", "/keys/my key.pem",
+				"-o", `ProxyCommand=ssh -i '/keys/my key.pem' -W %h:%p 'bastion.example.com'`,
+				"ec2-user@10.0.0.1", "docker exec -i c1 'ls'",
+			},
+		},
+		{
+			bastion: "admin@bastion.example.com:2222",
+			tty:     true,
+			command: []string{"ls"},
+			args: []string{
+				"-o", `ProxyCommand=ssh -p '2222' -W %h:%p 'admin@bastion.example.com'`,
+				"-t", "ec2-user@10.0.0.1", "docker exec -i -t c1 'ls'",
+			},
+		},
+		{
+			bastion: "admin@[2001:db8::1]:2222",
+			command: []string{"ls"},
+			args: []string{
+				"-o", `ProxyCommand=ssh -p '2222' -W %h:%p 'admin@2001:db8::1'`,
+				"ec2-user@10.0.0.1", "docker exec -i c1 'ls'",
+			},
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.args, sshArgs("ec2-user", "10.0.0.1", test.identityFile, test.bastion, test.tty, "c1", test.command))
+	}
+}
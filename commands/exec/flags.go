@@ -0,0 +1,25 @@
+package exec
+
+import "gopkg.in/alecthomas/kingpin.v2"
+
+type Flags struct {
+	AppName       *string
+	ClusterName   *string
+	TaskID        *string
+	ContainerName *string
+	SSHUser       *string
+	IdentityFile  *string
+	Bastion       *string
+}
+
+func NewFlags(kc *kingpin.CmdClause) *Flags {
+	return &Flags{
+		AppName:       kc.Flag("app-name", "App name").Default("").String(),
+		ClusterName:   kc.Flag("cluster-name", "Cluster name").Default("").String(),
+		TaskID:        kc.Flag("task", "ECS Task ID (default: any running task of the app)").Default("").String(),
+		ContainerName: kc.Flag("container", "Container to run the command in (default: app container)").Default("").String(),
+		SSHUser:       kc.Flag("ssh-user", "SSH user of the container instances").Default("ec2-user").String(),
+		IdentityFile:  kc.Flag("identity-file", "SSH private key (default: ~/.ssh/{cluster key pair}.pem if exists)").Short('i').Default("").String(),
+		Bastion:       kc.Flag("bastion", "Bastion (jump) host to connect through (\"[user@]host[:port]\", \"[user@][IPv6 address]:port\")").Default("").String(),
+	}
+}
@@ -0,0 +1,266 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/flags"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const timeFormat = "2006-01-02 15:04:05"
+
+type Command struct {
+	globalFlags  *flags.GlobalFlags
+	commandFlags *Flags
+	awsClient    *aws.Client
+}
+
+// History is the JSON output of the command.
+type History struct {
+	App       string      `json:"app"`
+	Cluster   string      `json:"cluster"`
+	Revisions []*Revision `json:"revisions"` // latest first
+}
+
+// Revision is an ECS Task Definition revision of the app, described by its app container.
+type Revision struct {
+	Revision          int64      `json:"revision"`
+	ARN               string     `json:"arn"`
+	RegisteredAt      *time.Time `json:"registered_at,omitempty"`
+	Image             string     `json:"image,omitempty"`
+	CPU               float64    `json:"cpu,omitempty"`
+	Memory            int64      `json:"memory,omitempty"`
+	MemoryReservation int64      `json:"memory_reservation,omitempty"`
+	PrimaryOn         []string   `json:"primary_on,omitempty"` // ECS Services whose PRIMARY deployment runs the revision
+	EnvDiff           *EnvDiff   `json:"env_diff,omitempty"`   // nil for the first revision
+
+	envs map[string]string
+}
+
+// EnvDiff is the difference of the app container's environment variables from the previous revision.
+type EnvDiff struct {
+	Added   map[string]string     `json:"added,omitempty"`
+	Changed map[string]*EnvChange `json:"changed,omitempty"`
+	Removed map[string]string     `json:"removed,omitempty"`
+}
+
+type EnvChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+func (c *Command) Init(ka *kingpin.Application, globalFlags *flags.GlobalFlags) *kingpin.CmdClause {
+	c.globalFlags = globalFlags
+
+	cmd := ka.Command("history",
+		"See: "+console.ColorFnHelpLink("https://github.com/coldbrewcloud/coldbrew-cli/wiki/CLI-Command:-history"))
+	c.commandFlags = NewFlags(cmd)
+
+	return cmd
+}
+
+func (c *Command) Run() error {
+	c.awsClient = c.globalFlags.GetAWSClient()
+
+	// JSON is the only output on stdout: errors still go to stderr
+	if conv.B(c.commandFlags.JSON) {
+		console.EnablePrintf(false)
+	}
+
+	// app configuration
+	conf, appName, clusterName, err := commands.ResolveApp(c.globalFlags, c.commandFlags.AppName, c.commandFlags.ClusterName)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+
+	// test if target cluster is available to use
+	console.ProcessingOnResource("Checking cluster availability", clusterName, false)
+	if err := commands.CheckClusterAvailability(c.awsClient, clusterName); err != nil {
+		return console.ExitWithError(core.NewErrorExtraInfo(err, "https://github.com/coldbrewcloud/coldbrew-cli/wiki/Error:-Cluster-not-found"))
+	}
+
+	ecsClusterName := core.DefaultECSClusterName(clusterName)
+
+	// blue/green: both ECS Services run revisions of the same family
+	ecsServiceNames, err := commands.AppECSServiceNames(c.awsClient, conf, appName, clusterName)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+
+	// ECS Services by the revision of their PRIMARY deployment
+	primaryOn := make(map[string][]string)
+	for _, ecsServiceName := range ecsServiceNames {
+		ecsService, err := c.awsClient.ECS().RetrieveService(ecsClusterName, ecsServiceName)
+		if err != nil {
+			return console.ExitWithErrorString("Failed to retrieve ECS Service [%s/%s]: %s", ecsClusterName, ecsServiceName, err.Error())
+		}
+		if ecsService == nil || conv.S(ecsService.Status) != "ACTIVE" {
+			continue
+		}
+		for _, d := range ecsService.Deployments {
+			if conv.S(d.Status) == "PRIMARY" {
+				arn := conv.S(d.TaskDefinition)
+				primaryOn[arn] = append(primaryOn[arn], ecsServiceName)
+			}
+		}
+	}
+
+	// ECS Task Definition revisions (oldest first), and the one before the oldest listed to diff it against
+	ecsTaskDefinitionName := core.DefaultECSTaskDefinitionName(appName)
+	ecsTaskDefinitionARNs, err := c.awsClient.ECS().ListTaskDefinitionARNs(ecsTaskDefinitionName)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to list ECS Task Definitions [%s]: %s", ecsTaskDefinitionName, err.Error())
+	}
+	first := 0
+	if limit := int(conv.U16(c.commandFlags.Limit)); limit > 0 && len(ecsTaskDefinitionARNs) > limit {
+		first = len(ecsTaskDefinitionARNs) - limit
+	}
+
+	revisions := []*Revision{} // latest first
+	var previous *Revision
+	start := first - 1
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < len(ecsTaskDefinitionARNs); i++ {
+		arn := ecsTaskDefinitionARNs[i]
+		ecsTaskDefinition, err := c.awsClient.ECS().RetrieveTaskDefinition(arn)
+		if err != nil {
+			return console.ExitWithErrorString("Failed to retrieve ECS Task Definition [%s]: %s", aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(arn), err.Error())
+		}
+		revision := newRevision(ecsTaskDefinition, core.DefaultECSTaskMainContainerName(appName))
+		revision.PrimaryOn = primaryOn[revision.ARN]
+		if previous != nil {
+			revision.EnvDiff = envDiff(previous.envs, revision.envs)
+		}
+		previous = revision
+
+		if i >= first {
+			revisions = append([]*Revision{revision}, revisions...)
+		}
+	}
+
+	if conv.B(c.commandFlags.JSON) {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(&History{App: appName, Cluster: clusterName, Revisions: revisions}); err != nil {
+			return console.ExitWithErrorString("Failed to write JSON: %s", err.Error())
+		}
+		return nil
+	}
+
+	if len(revisions) == 0 {
+		console.Info(fmt.Sprintf("No ECS Task Definitions [%s] found", ecsTaskDefinitionName))
+		return nil
+	}
+	for _, revision := range revisions {
+		printRevision(ecsTaskDefinitionName, revision, len(ecsServiceNames) > 1)
+	}
+
+	return nil
+}
+
+// newRevision describes the ECS Task Definition by its app container.
+func newRevision(ecsTaskDefinition *_ecs.TaskDefinition, appContainerName string) *Revision {
+	revision := &Revision{
+		Revision:     conv.I64(ecsTaskDefinition.Revision),
+		ARN:          conv.S(ecsTaskDefinition.TaskDefinitionArn),
+		RegisteredAt: ecsTaskDefinition.RegisteredAt,
+		envs:         make(map[string]string),
+	}
+
+	for _, containerDefinition := range ecsTaskDefinition.ContainerDefinitions {
+		if conv.S(containerDefinition.Name) != appContainerName {
+			continue
+		}
+		revision.Image = conv.S(containerDefinition.Image)
+		revision.CPU = float64(conv.I64(containerDefinition.Cpu)) / 1024.0
+		revision.Memory = conv.I64(containerDefinition.Memory)
+		revision.MemoryReservation = conv.I64(containerDefinition.MemoryReservation)
+		for _, kv := range containerDefinition.Environment {
+			revision.envs[conv.S(kv.Name)] = conv.S(kv.Value)
+		}
+	}
+
+	return revision
+}
+
+// envDiff returns the differences between two sets of environment variables.
+func envDiff(before, after map[string]string) *EnvDiff {
+	diff := &EnvDiff{
+		Added:   make(map[string]string),
+		Changed: make(map[string]*EnvChange),
+		Removed: make(map[string]string),
+	}
+	for name, a := range after {
+		if b, ok := before[name]; !ok {
+			diff.Added[name] = a
+		} else if b != a {
+			diff.Changed[name] = &EnvChange{Before: b, After: a}
+		}
+	}
+	for name, b := range before {
+		if _, ok := after[name]; !ok {
+			diff.Removed[name] = b
+		}
+	}
+	return diff
+}
+
+func printRevision(ecsTaskDefinitionName string, revision *Revision, blueGreen bool) {
+	console.Info(fmt.Sprintf("%s:%d", ecsTaskDefinitionName, revision.Revision))
+
+	if len(revision.PrimaryOn) > 0 {
+		note := "(PRIMARY)"
+		if blueGreen {
+			note = fmt.Sprintf("(PRIMARY on %s)", strings.Join(revision.PrimaryOn, ", "))
+		}
+		console.DetailWithResourceNote("Status", "current", note, false)
+	}
+	if revision.RegisteredAt != nil {
+		console.DetailWithResource("Registered", revision.RegisteredAt.Local().Format(timeFormat))
+	}
+	if revision.Image != "" {
+		console.DetailWithResource("Image", revision.Image)
+		console.DetailWithResource("CPU", fmt.Sprintf("%.2f", revision.CPU))
+		console.DetailWithResource("Memory", fmt.Sprintf("%dm", revision.Memory))
+		if revision.MemoryReservation > 0 {
+			console.DetailWithResource("Memory Reservation", fmt.Sprintf("%dm", revision.MemoryReservation))
+		}
+	}
+
+	if revision.EnvDiff == nil {
+		return
+	}
+	names := []string{}
+	for name := range revision.EnvDiff.Added {
+		names = append(names, name)
+	}
+	for name := range revision.EnvDiff.Changed {
+		names = append(names, name)
+	}
+	for name := range revision.EnvDiff.Removed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if v, ok := revision.EnvDiff.Added[name]; ok {
+			console.DetailWithResourceNote("Env "+name, fmt.Sprintf("%q", v), "(added)", false)
+		} else if change, ok := revision.EnvDiff.Changed[name]; ok {
+			console.PlanChange("Env "+name, fmt.Sprintf("%q", change.Before), fmt.Sprintf("%q", change.After))
+		} else {
+			console.DetailWithResourceNote("Env "+name, fmt.Sprintf("%q", revision.EnvDiff.Removed[name]), "(removed)", true)
+		}
+	}
+}
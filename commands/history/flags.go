@@ -0,0 +1,19 @@
+package history
+
+import "gopkg.in/alecthomas/kingpin.v2"
+
+type Flags struct {
+	AppName     *string
+	ClusterName *string
+	Limit       *uint16
+	JSON        *bool
+}
+
+func NewFlags(kc *kingpin.CmdClause) *Flags {
+	return &Flags{
+		AppName:     kc.Flag("app-name", "App name").Default("").String(),
+		ClusterName: kc.Flag("cluster-name", "Cluster name").Default("").String(),
+		Limit:       kc.Flag("limit", "Number of latest revisions to list (0: all)").Default("10").Uint16(),
+		JSON:        kc.Flag("json", "Print revisions in JSON").Bool(),
+	}
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+// AWSLogsStream returns the CloudWatch Logs group and stream names that the container of the ECS Task sends its
+// logs to. Group name is empty if the container does not use the awslogs log driver. Stream name is empty if it's
+// not known yet: without a stream prefix, the stream is named after the Docker container, which exists only
+// after the task started.
+func AWSLogsStream(containerDefinition *_ecs.ContainerDefinition, task *_ecs.Task) (string, string) {
+	logConfiguration := containerDefinition.LogConfiguration
+	if logConfiguration == nil || conv.S(logConfiguration.LogDriver) != aws.ECSTaskDefinitionLogDriverAWSLogs {
+		return "", ""
+	}
+	groupName := conv.S(logConfiguration.Options["awslogs-group"])
+	containerName := conv.S(containerDefinition.Name)
+
+	// format: "{prefix}/{container name}/{task ID}"
+	if prefix := conv.S(logConfiguration.Options["awslogs-stream-prefix"]); prefix != "" {
+		return groupName, prefix + "/" + containerName + "/" + aws.GetECSTaskIDFromARN(conv.S(task.TaskArn))
+	}
+
+	// format: "{Docker container ID}"
+	for _, container := range task.Containers {
+		if conv.S(container.Name) == containerName {
+			return groupName, conv.S(container.RuntimeId)
+		}
+	}
+	return groupName, ""
+}
@@ -0,0 +1,199 @@
+package logs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/flags"
+	"github.com/coldbrewcloud/coldbrew-cli/utils"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	followPollInterval = 5 * time.Second
+
+	// CloudWatch Logs does not deliver the events of different streams in order: each poll looks back this far
+	// from the latest event printed so that late events of the other streams are not missed.
+	followLookback = 3 * followPollInterval
+
+	// maximum number of FilterLogEvents pages read at a time
+	maxPagesPerPoll = 10
+)
+
+type Command struct {
+	globalFlags  *flags.GlobalFlags
+	commandFlags *Flags
+	awsClient    *aws.Client
+}
+
+func (c *Command) Init(ka *kingpin.Application, globalFlags *flags.GlobalFlags) *kingpin.CmdClause {
+	c.globalFlags = globalFlags
+
+	cmd := ka.Command("logs",
+		"See: "+console.ColorFnHelpLink("https://github.com/coldbrewcloud/coldbrew-cli/wiki/CLI-Command:-logs"))
+	c.commandFlags = NewFlags(cmd)
+
+	return cmd
+}
+
+func (c *Command) Run() error {
+	c.awsClient = c.globalFlags.GetAWSClient()
+
+	// app configuration
+	conf, appName, clusterName, err := commands.ResolveApp(c.globalFlags, c.commandFlags.AppName, c.commandFlags.ClusterName)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+
+	sinceSeconds, err := core.ParseTimeExpression(conv.S(c.commandFlags.Since))
+	if err != nil {
+		return console.ExitWithErrorString("Invalid since [%s]", conv.S(c.commandFlags.Since))
+	}
+
+	// CloudWatch Logs group: same as deploy
+	groupName := core.DefaultCloudWatchLogsGroupName(appName, clusterName)
+	if commands.IsAppConfig(conf, appName, clusterName) {
+		if conv.S(conf.Logging.Driver) != aws.ECSTaskDefinitionLogDriverAWSLogs {
+			return console.ExitWithErrorString("App [%s] does not use the [%s] log driver", appName, aws.ECSTaskDefinitionLogDriverAWSLogs)
+		}
+		if name, ok := conf.Logging.Options["awslogs-group"]; ok && !utils.IsBlank(name) {
+			groupName = name
+		}
+	}
+	groups, err := c.awsClient.CloudWatchLogs().ListGroups(groupName)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to list CloudWatch Logs Group [%s]: %s", groupName, err.Error())
+	}
+	groupFound := false
+	for _, group := range groups {
+		if conv.S(group.LogGroupName) == groupName {
+			groupFound = true
+			break
+		}
+	}
+	if !groupFound {
+		return console.ExitWithErrorString("CloudWatch Logs Group [%s] not found", groupName)
+	}
+
+	// with the task ID, search the log streams of its containers only
+	var streamNames []string
+	if taskID := strings.TrimSpace(conv.S(c.commandFlags.TaskID)); taskID != "" {
+		streamNames, err = c.taskLogStreamNames(core.DefaultECSClusterName(clusterName), groupName, taskID)
+		if err != nil {
+			return console.ExitWithError(err)
+		}
+	}
+
+	appContainerName := core.DefaultECSTaskMainContainerName(appName)
+	startTime := time.Now().Add(-time.Duration(sinceSeconds)*time.Second).UnixNano() / int64(time.Millisecond)
+	lastTimestamp := startTime
+	printed := map[string]int64{} // event ID -> timestamp
+	var nextToken *string
+	for {
+		events, token, err := c.awsClient.CloudWatchLogs().FilterLogEvents(groupName, streamNames, conv.S(c.commandFlags.Filter), startTime, nextToken, maxPagesPerPoll)
+		if err != nil {
+			return console.ExitWithErrorString("Failed to retrieve CloudWatch Logs [%s]: %s", groupName, err.Error())
+		}
+
+		// merge streams in timestamp order
+		sort.SliceStable(events, func(i, j int) bool {
+			return conv.I64(events[i].Timestamp) < conv.I64(events[j].Timestamp)
+		})
+
+		for _, event := range events {
+			eventID := conv.S(event.EventId)
+			if _, ok := printed[eventID]; ok {
+				continue
+			}
+			printed[eventID] = conv.I64(event.Timestamp)
+			if conv.I64(event.Timestamp) > lastTimestamp {
+				lastTimestamp = conv.I64(event.Timestamp)
+			}
+
+			source := streamSource(conv.S(event.LogStreamName), appContainerName)
+			console.LogEvent(source, strings.TrimRight(conv.S(event.Message), "\n"))
+		}
+
+		if !conv.B(c.commandFlags.Follow) {
+			if token != nil {
+				console.Info("More log events are available: narrow them down with --since, --filter or --task.")
+			}
+			return nil
+		}
+
+		// read the rest of the events first
+		nextToken = token
+		if nextToken != nil {
+			continue
+		}
+
+		// poll again from a little before the latest event: the events already printed are filtered out by event ID
+		if lookbackTime := lastTimestamp - int64(followLookback/time.Millisecond); lookbackTime > startTime {
+			startTime = lookbackTime
+			for eventID, timestamp := range printed {
+				if timestamp < startTime {
+					delete(printed, eventID)
+				}
+			}
+		}
+
+		time.Sleep(followPollInterval)
+	}
+}
+
+// taskLogStreamNames returns the names of the log streams in the CloudWatch Logs group that the containers of
+// the ECS Task send their logs to.
+func (c *Command) taskLogStreamNames(ecsClusterName, groupName, taskID string) ([]string, error) {
+	tasks, err := c.awsClient.ECS().RetrieveTasks(ecsClusterName, []string{taskID})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve ECS Task [%s]: %s", taskID, err.Error())
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("ECS Task [%s] was not found.", taskID)
+	}
+	task := tasks[0]
+
+	ecsTaskDefinition, err := c.awsClient.ECS().RetrieveTaskDefinition(conv.S(task.TaskDefinitionArn))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve ECS Task Definition [%s]: %s", conv.S(task.TaskDefinitionArn), err.Error())
+	}
+
+	streamNames := []string{}
+	for _, containerDefinition := range ecsTaskDefinition.ContainerDefinitions {
+		if streamGroupName, streamName := commands.AWSLogsStream(containerDefinition, task); streamGroupName == groupName && streamName != "" {
+			streamNames = append(streamNames, streamName)
+		}
+	}
+	if len(streamNames) == 0 {
+		return nil, fmt.Errorf("ECS Task [%s] has no log streams in CloudWatch Logs Group [%s].", taskID, groupName)
+	}
+
+	return streamNames, nil
+}
+
+// streamSource returns the source to prefix the log lines of the log stream with: ECS Task ID, followed by the
+// container name for containers other than the app container. Log streams created without the stream prefix are
+// named after the Docker container: source is its short ID.
+func streamSource(streamName, appContainerName string) string {
+	// format: "{prefix}/{container name}/{task ID}"
+	tokens := strings.Split(streamName, "/")
+	if len(tokens) != 3 {
+		if len(streamName) > 12 {
+			return streamName[:12]
+		}
+		return streamName
+	}
+
+	taskID, containerName := tokens[2], tokens[1]
+	if containerName == appContainerName {
+		return taskID
+	}
+	return taskID + "/" + containerName
+}
@@ -0,0 +1,23 @@
+package logs
+
+import "gopkg.in/alecthomas/kingpin.v2"
+
+type Flags struct {
+	AppName     *string
+	ClusterName *string
+	Follow      *bool
+	Since       *string
+	Filter      *string
+	TaskID      *string
+}
+
+func NewFlags(kc *kingpin.CmdClause) *Flags {
+	return &Flags{
+		AppName:     kc.Flag("app-name", "App name").Default("").String(),
+		ClusterName: kc.Flag("cluster-name", "Cluster name").Default("").String(),
+		Follow:      kc.Flag("follow", "Keep printing new log events").Short('f').Bool(),
+		Since:       kc.Flag("since", "Print log events since (e.g. \"10m\", \"2h\")").Default("10m").String(),
+		Filter:      kc.Flag("filter", "CloudWatch Logs filter pattern").Default("").String(),
+		TaskID:      kc.Flag("task", "Print log events of the ECS Task only").Default("").String(),
+	}
+}
@@ -0,0 +1,208 @@
+package rollback
+
+import (
+	"fmt"
+
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
+	"github.com/coldbrewcloud/coldbrew-cli/config"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/flags"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	globalFlags  *flags.GlobalFlags
+	commandFlags *Flags
+	awsClient    *aws.Client
+}
+
+func (c *Command) Init(ka *kingpin.Application, globalFlags *flags.GlobalFlags) *kingpin.CmdClause {
+	c.globalFlags = globalFlags
+
+	cmd := ka.Command("rollback",
+		"See: "+console.ColorFnHelpLink("https://github.com/coldbrewcloud/coldbrew-cli/wiki/CLI-Command:-rollback"))
+	c.commandFlags = NewFlags(cmd)
+
+	return cmd
+}
+
+func (c *Command) Run() error {
+	c.awsClient = c.globalFlags.GetAWSClient()
+
+	// app configuration
+	conf, appName, clusterName, err := commands.ResolveApp(c.globalFlags, c.commandFlags.AppName, c.commandFlags.ClusterName)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+
+	// test if target cluster is available to use
+	console.ProcessingOnResource("Checking cluster availability", clusterName, false)
+	if err := commands.CheckClusterAvailability(c.awsClient, clusterName); err != nil {
+		return console.ExitWithError(core.NewErrorExtraInfo(err, "https://github.com/coldbrewcloud/coldbrew-cli/wiki/Error:-Cluster-not-found"))
+	}
+
+	ecsClusterName := core.DefaultECSClusterName(clusterName)
+	ecsServiceName := core.DefaultECSServiceName(appName)
+
+	// blue/green: switch back to the previous ECS Service if it's still running,
+	// or roll back whichever ECS Service is live
+	if commands.IsAppConfig(conf, appName, clusterName) && conv.B(conf.LoadBalancer.BlueGreen.Enabled) {
+		state, err := commands.RetrieveBlueGreenState(c.awsClient, conf)
+		if err != nil {
+			return console.ExitWithError(err)
+		}
+		switched, err := c.switchBlueGreen(conf, ecsClusterName, state)
+		if err != nil {
+			return console.ExitWithError(err)
+		}
+		if switched {
+			return nil
+		}
+		ecsServiceName = state.Live.ECSServiceName
+	}
+
+	// ECS Service
+	ecsService, err := c.awsClient.ECS().RetrieveService(ecsClusterName, ecsServiceName)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to retrieve ECS Service [%s/%s]: %s", ecsClusterName, ecsServiceName, err.Error())
+	}
+	if ecsService == nil || conv.S(ecsService.Status) != "ACTIVE" {
+		return console.ExitWithErrorString("ECS Service [%s/%s] not found", ecsClusterName, ecsServiceName)
+	}
+	currentTaskDefinitionARN := conv.S(ecsService.TaskDefinition)
+
+	// find target ECS Task Definition revision
+	ecsTaskDefinitionName := core.DefaultECSTaskDefinitionName(appName)
+	ecsTaskDefinitionARNs, err := c.awsClient.ECS().ListTaskDefinitionARNs(ecsTaskDefinitionName)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to list ECS Task Definitions [%s]: %s", ecsTaskDefinitionName, err.Error())
+	}
+	targetTaskDefinitionARN, err := c.findTargetTaskDefinitionARN(ecsTaskDefinitionARNs, currentTaskDefinitionARN, conv.I64(c.commandFlags.ToRevision))
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+
+	console.Info("Rollback")
+	console.DetailWithResource("ECS Service", ecsServiceName)
+	console.DetailWithResource("Current", aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(currentTaskDefinitionARN))
+	console.DetailWithResource("Target", aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(targetTaskDefinitionARN))
+	console.Blank()
+
+	// confirmation
+	if !conv.B(c.commandFlags.NoConfirm) && !console.AskConfirm("Do you want to roll back the application?", false) {
+		return nil
+	}
+
+	console.Blank()
+
+	// update ECS service (keeping the current desired count)
+	console.UpdatingResource("Updating ECS Service", ecsServiceName, false)
+	_, err = c.awsClient.ECS().UpdateService(ecsClusterName, ecsServiceName, targetTaskDefinitionARN, nil, nil, nil)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to update ECS Service [%s]: %s", ecsServiceName, err.Error())
+	}
+
+	// scheduled tasks run the same revision as the ECS Service
+	if err := commands.UpdateScheduleTargets(c.awsClient, clusterName, appName, targetTaskDefinitionARN); err != nil {
+		return console.ExitWithError(err)
+	}
+
+	console.Blank()
+	console.Info("Application rollback completed.")
+
+	return nil
+}
+
+// findTargetTaskDefinitionARN returns the ARN of the given revision, or the latest revision
+// older than the current one if toRevision is 0.
+func (c *Command) findTargetTaskDefinitionARN(taskDefinitionARNs []string, currentTaskDefinitionARN string, toRevision int64) (string, error) {
+	currentRevision := aws.GetECSTaskDefinitionRevisionFromARN(currentTaskDefinitionARN)
+
+	if toRevision > 0 {
+		if toRevision == currentRevision {
+			return "", fmt.Errorf("ECS Service is already running revision [%d].", toRevision)
+		}
+		for _, arn := range taskDefinitionARNs {
+			if aws.GetECSTaskDefinitionRevisionFromARN(arn) == toRevision {
+				return arn, nil
+			}
+		}
+		return "", fmt.Errorf("ECS Task Definition revision [%d] not found.", toRevision)
+	}
+
+	targetARN := ""
+	targetRevision := int64(0)
+	for _, arn := range taskDefinitionARNs {
+		revision := aws.GetECSTaskDefinitionRevisionFromARN(arn)
+		if revision < currentRevision && revision > targetRevision {
+			targetARN = arn
+			targetRevision = revision
+		}
+	}
+	if targetARN == "" {
+		return "", fmt.Errorf("No ECS Task Definition revision older than [%d] found.", currentRevision)
+	}
+
+	return targetARN, nil
+}
+
+// switchBlueGreen switches the ELB listeners back to the idle ECS Service if it's still running (e.g. during
+// the bake time of a blue/green deployment) and the target revision matches. It returns false if it cannot
+// switch back, in which case the live ECS Service should be rolled back instead.
+func (c *Command) switchBlueGreen(conf *config.Config, ecsClusterName string, state *commands.BlueGreenState) (bool, error) {
+	if state.Live.ECSService == nil || state.Idle.ECSService == nil || len(state.Listeners) == 0 ||
+		conv.I64(state.Idle.ECSService.RunningCount) == 0 {
+		return false, nil
+	}
+
+	idleTaskDefinitionARN := conv.S(state.Idle.ECSService.TaskDefinition)
+	toRevision := conv.I64(c.commandFlags.ToRevision)
+	if toRevision > 0 && toRevision != aws.GetECSTaskDefinitionRevisionFromARN(idleTaskDefinitionARN) {
+		return false, nil
+	}
+
+	console.Info("Rollback (blue/green)")
+	console.DetailWithResource("ECS Service (live)", fmt.Sprintf("%s (%s)",
+		state.Live.ECSServiceName, aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(conv.S(state.Live.ECSService.TaskDefinition))))
+	console.DetailWithResource("ECS Service (previous)", fmt.Sprintf("%s (%s)",
+		state.Idle.ECSServiceName, aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(idleTaskDefinitionARN)))
+	console.Blank()
+
+	// confirmation
+	if !conv.B(c.commandFlags.NoConfirm) && !console.AskConfirm("Do you want to switch back to the previous ECS Service?", false) {
+		return true, nil
+	}
+
+	console.Blank()
+
+	if err := commands.SwitchBlueGreenListeners(c.awsClient, state); err != nil {
+		return false, err
+	}
+
+	// scheduled tasks run the same revision as the live ECS Service
+	if err := commands.UpdateScheduleTargets(c.awsClient, conv.S(conf.ClusterName), conv.S(conf.Name), idleTaskDefinitionARN); err != nil {
+		return false, err
+	}
+
+	// auto scaling moves to the live version before the version that was switched away from is stopped
+	if err := commands.UpdateAutoScaling(c.awsClient, conf, ecsClusterName, state.Live.ECSServiceName); err != nil {
+		return false, err
+	}
+	if err := commands.DeregisterAutoScaling(c.awsClient, ecsClusterName, state.Idle.ECSServiceName); err != nil {
+		return false, err
+	}
+
+	// stop the version that was switched away from
+	console.UpdatingResource("Updating ECS Service to stop all tasks", state.Idle.ECSServiceName, false)
+	if _, err := c.awsClient.ECS().UpdateService(ecsClusterName, state.Idle.ECSServiceName, conv.S(state.Idle.ECSService.TaskDefinition), conv.U16P(0), nil, nil); err != nil {
+		return false, fmt.Errorf("Failed to update ECS Service [%s]: %s", state.Idle.ECSServiceName, err.Error())
+	}
+
+	console.Blank()
+	console.Info("Application rollback completed.")
+
+	return true, nil
+}
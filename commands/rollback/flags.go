@@ -0,0 +1,19 @@
+package rollback
+
+import "gopkg.in/alecthomas/kingpin.v2"
+
+type Flags struct {
+	AppName     *string
+	ClusterName *string
+	ToRevision  *int64
+	NoConfirm   *bool
+}
+
+func NewFlags(kc *kingpin.CmdClause) *Flags {
+	return &Flags{
+		AppName:     kc.Flag("app-name", "App name").Default("").String(),
+		ClusterName: kc.Flag("cluster-name", "Cluster name").Default("").String(),
+		ToRevision:  kc.Flag("to-revision", "Task definition revision to roll back to (default: previous revision)").Default("0").Int64(),
+		NoConfirm:   kc.Flag("yes", "Roll back with no confirmation").Short('y').Default("false").Bool(),
+	}
+}
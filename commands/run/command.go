@@ -0,0 +1,218 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/flags"
+	"github.com/coldbrewcloud/coldbrew-cli/utils"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	runPollInterval = 3 * time.Second
+	runStartedBy    = "coldbrew-run"
+)
+
+type Command struct {
+	globalFlags  *flags.GlobalFlags
+	commandFlags *Flags
+	awsClient    *aws.Client
+	commandArg   *[]string
+}
+
+func (c *Command) Init(ka *kingpin.Application, globalFlags *flags.GlobalFlags) *kingpin.CmdClause {
+	c.globalFlags = globalFlags
+
+	cmd := ka.Command("run",
+		"See: "+console.ColorFnHelpLink("https://github.com/coldbrewcloud/coldbrew-cli/wiki/CLI-Command:-run"))
+	c.commandFlags = NewFlags(cmd)
+
+	c.commandArg = cmd.Arg("command", "Command to run (use \"--\" before the command if it has flags)").Required().Strings()
+
+	return cmd
+}
+
+func (c *Command) Run() error {
+	c.awsClient = c.globalFlags.GetAWSClient()
+
+	// app configuration
+	conf, appName, clusterName, err := commands.ResolveApp(c.globalFlags, c.commandFlags.AppName, c.commandFlags.ClusterName)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+
+	timeoutSeconds, err := core.ParseTimeExpression(conv.S(c.commandFlags.WaitTimeout))
+	if err != nil {
+		return console.ExitWithErrorString("Invalid wait timeout [%s]", conv.S(c.commandFlags.WaitTimeout))
+	}
+
+	// test if target cluster is available to use
+	console.ProcessingOnResource("Checking cluster availability", clusterName, false)
+	if err := commands.CheckClusterAvailability(c.awsClient, clusterName); err != nil {
+		return console.ExitWithError(core.NewErrorExtraInfo(err, "https://github.com/coldbrewcloud/coldbrew-cli/wiki/Error:-Cluster-not-found"))
+	}
+
+	ecsClusterName := core.DefaultECSClusterName(clusterName)
+
+	// blue/green: use the ECS Service that's live
+	ecsServiceNames, err := commands.AppECSServiceNames(c.awsClient, conf, appName, clusterName)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+	ecsServiceName := ecsServiceNames[0]
+
+	// current ECS Task Definition of the app
+	ecsService, err := c.awsClient.ECS().RetrieveService(ecsClusterName, ecsServiceName)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to retrieve ECS Service [%s/%s]: %s", ecsClusterName, ecsServiceName, err.Error())
+	}
+	if ecsService == nil || conv.S(ecsService.Status) != "ACTIVE" {
+		return console.ExitWithErrorString("ECS Service [%s/%s] not found", ecsClusterName, ecsServiceName)
+	}
+	ecsTaskDefinitionARN := conv.S(ecsService.TaskDefinition)
+	ecsTaskDefinition, err := c.awsClient.ECS().RetrieveTaskDefinition(ecsTaskDefinitionARN)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to retrieve ECS Task Definition [%s]: %s", ecsTaskDefinitionARN, err.Error())
+	}
+
+	containerName := conv.S(c.commandFlags.ContainerName)
+	if utils.IsBlank(containerName) {
+		containerName = core.DefaultECSTaskMainContainerName(appName)
+	}
+	var containerDefinition *_ecs.ContainerDefinition
+	for _, cd := range ecsTaskDefinition.ContainerDefinitions {
+		if conv.S(cd.Name) == containerName {
+			containerDefinition = cd
+			break
+		}
+	}
+	if containerDefinition == nil {
+		return console.ExitWithErrorString("Container [%s] not found in ECS Task Definition [%s]",
+			containerName, aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(ecsTaskDefinitionARN))
+	}
+
+	// run ECS Task
+	command := *c.commandArg
+	console.ProcessingOnResource("Running ECS Task", aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(ecsTaskDefinitionARN), false)
+	console.DetailWithResource("Container", containerName)
+	console.DetailWithResource("Command", strings.Join(command, " "))
+	ecsTask, err := c.awsClient.ECS().RunTask(ecsClusterName, ecsTaskDefinitionARN, containerName, command, *c.commandFlags.Envs, runStartedBy)
+	if err != nil {
+		return console.ExitWithErrorString("Failed to run ECS Task [%s]: %s", aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(ecsTaskDefinitionARN), err.Error())
+	}
+	ecsTaskARN := conv.S(ecsTask.TaskArn)
+	console.DetailWithResource("ECS Task", aws.GetECSTaskIDFromARN(ecsTaskARN))
+
+	// wait for the task to stop
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	ecsTask, err = c.waitForTaskStopped(ecsClusterName, ecsTaskARN, containerDefinition, timeout)
+	if err != nil {
+		return console.ExitWithError(err)
+	}
+
+	// exit code of the container
+	for _, container := range ecsTask.Containers {
+		if conv.S(container.Name) != containerName {
+			continue
+		}
+		if container.ExitCode == nil {
+			reason := conv.S(container.Reason)
+			if reason == "" {
+				reason = conv.S(ecsTask.StoppedReason)
+			}
+			return console.ExitWithErrorString("Container [%s] did not run: %s", containerName, reason)
+		}
+
+		exitCode := conv.I64(container.ExitCode)
+		if exitCode != 0 {
+			console.DetailWithResourceNote("Exit Code", conv.I64S(exitCode), "(failed)", true)
+			os.Exit(int(exitCode))
+		}
+		console.DetailWithResource("Exit Code", conv.I64S(exitCode))
+		return nil
+	}
+
+	return console.ExitWithErrorString("Container [%s] not found in ECS Task [%s]", containerName, aws.GetECSTaskIDFromARN(ecsTaskARN))
+}
+
+// waitForTaskStopped waits until the ECS Task stops, printing the logs of the container if it uses the awslogs
+// log driver.
+func (c *Command) waitForTaskStopped(ecsClusterName, ecsTaskARN string, containerDefinition *_ecs.ContainerDefinition, timeout time.Duration) (*_ecs.Task, error) {
+	ecsTaskID := aws.GetECSTaskIDFromARN(ecsTaskARN)
+	console.ProcessingOnResource("Waiting for ECS Task to stop", ecsTaskID, true)
+
+	deadline := time.Now().Add(timeout)
+	lastStatus := ""
+	var logsNextToken *string
+	for {
+		ecsTasks, err := c.awsClient.ECS().RetrieveTasks(ecsClusterName, []string{ecsTaskARN})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve ECS Task [%s]: %s", ecsTaskID, err.Error())
+		}
+		if len(ecsTasks) == 0 {
+			return nil, fmt.Errorf("ECS Task [%s] not found", ecsTaskID)
+		}
+		ecsTask := ecsTasks[0]
+
+		// print status only when it changes
+		if status := conv.S(ecsTask.LastStatus); status != lastStatus {
+			console.DetailWithResource("Status", status)
+			lastStatus = status
+		}
+
+		logsNextToken, err = c.printLogs(containerDefinition, ecsTask, logsNextToken)
+		if err != nil {
+			return nil, err
+		}
+
+		if conv.S(ecsTask.LastStatus) == _ecs.DesiredStatusStopped {
+			// last log events can take a few seconds to be delivered to CloudWatch Logs
+			if groupName, _ := commands.AWSLogsStream(containerDefinition, ecsTask); groupName != "" {
+				time.Sleep(runPollInterval)
+				if _, err := c.printLogs(containerDefinition, ecsTask, logsNextToken); err != nil {
+					return nil, err
+				}
+			}
+			return ecsTask, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("ECS Task [%s] did not stop within %s: it's still running.", ecsTaskID, timeout.String())
+		}
+
+		time.Sleep(runPollInterval)
+	}
+}
+
+// printLogs prints the log events of the container since nextToken, and returns the token to continue from.
+func (c *Command) printLogs(containerDefinition *_ecs.ContainerDefinition, ecsTask *_ecs.Task, nextToken *string) (*string, error) {
+	groupName, streamName := commands.AWSLogsStream(containerDefinition, ecsTask)
+	if groupName == "" || streamName == "" {
+		return nextToken, nil
+	}
+
+	for {
+		events, next, err := c.awsClient.CloudWatchLogs().RetrieveLogEvents(groupName, streamName, nextToken)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve CloudWatch Logs [%s/%s]: %s", groupName, streamName, err.Error())
+		}
+		for _, event := range events {
+			console.ShellOutput(conv.S(event.Message))
+		}
+
+		// same token is returned at the end of the stream
+		if len(events) == 0 || next == nil || conv.S(next) == conv.S(nextToken) {
+			return next, nil
+		}
+		nextToken = next
+	}
+}
@@ -0,0 +1,21 @@
+package run
+
+import "gopkg.in/alecthomas/kingpin.v2"
+
+type Flags struct {
+	AppName       *string
+	ClusterName   *string
+	ContainerName *string
+	Envs          *map[string]string
+	WaitTimeout   *string
+}
+
+func NewFlags(kc *kingpin.CmdClause) *Flags {
+	return &Flags{
+		AppName:       kc.Flag("app-name", "App name").Default("").String(),
+		ClusterName:   kc.Flag("cluster-name", "Cluster name").Default("").String(),
+		ContainerName: kc.Flag("container", "Container to run the command in (default: app container)").Default("").String(),
+		Envs:          kc.Flag("env", "Additional environment variable (\"key=value\")").Short('E').StringMap(),
+		WaitTimeout:   kc.Flag("wait-timeout", "Maximum time to wait for the task to stop").Default("1h").String(),
+	}
+}
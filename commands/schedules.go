@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"fmt"
+
+	_events "github.com/aws/aws-sdk-go/service/cloudwatchevents"
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
+	"github.com/coldbrewcloud/coldbrew-cli/console"
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+// UpdateScheduleTargets points the ECS task targets of the app's CloudWatch Events Rules to the ECS Task Definition,
+// so that the scheduled tasks run the same revision as the ECS Service. Schedule expressions, task counts, and
+// command overrides are kept.
+func UpdateScheduleTargets(awsClient *aws.Client, clusterName, appName, ecsTaskDefinitionARN string) error {
+	rulePrefix := core.DefaultCloudWatchEventsRuleNamePrefix(clusterName, appName)
+	ruleNames, err := awsClient.CloudWatchEvents().ListRuleNames(rulePrefix)
+	if err != nil {
+		return fmt.Errorf("Failed to list CloudWatch Events Rules [%s*]: %s", rulePrefix, err.Error())
+	}
+
+	for _, ruleName := range ruleNames {
+		targets, err := awsClient.CloudWatchEvents().ListTargets(ruleName)
+		if err != nil {
+			return fmt.Errorf("Failed to list targets of CloudWatch Events Rule [%s]: %s", ruleName, err.Error())
+		}
+
+		updatedTargets := []*_events.Target{}
+		for _, target := range targets {
+			if target.EcsParameters == nil || conv.S(target.EcsParameters.TaskDefinitionArn) == ecsTaskDefinitionARN {
+				continue
+			}
+			target.EcsParameters.TaskDefinitionArn = conv.SP(ecsTaskDefinitionARN)
+			updatedTargets = append(updatedTargets, target)
+		}
+		if len(updatedTargets) == 0 {
+			continue
+		}
+
+		console.UpdatingResource("Updating CloudWatch Events Rule", ruleName, false)
+		if err := awsClient.CloudWatchEvents().PutTargets(ruleName, updatedTargets); err != nil {
+			return fmt.Errorf("Failed to update targets of CloudWatch Events Rule [%s]: %s", ruleName, err.Error())
+		}
+	}
+
+	return nil
+}
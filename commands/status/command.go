@@ -2,11 +2,12 @@ package status
 
 import (
 	"fmt"
-	"io/ioutil"
 	"strings"
 
+	_aws "github.com/aws/aws-sdk-go/aws"
+	_ecs "github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/coldbrewcloud/coldbrew-cli/aws"
-	"github.com/coldbrewcloud/coldbrew-cli/config"
+	"github.com/coldbrewcloud/coldbrew-cli/commands"
 	"github.com/coldbrewcloud/coldbrew-cli/console"
 	"github.com/coldbrewcloud/coldbrew-cli/core"
 	"github.com/coldbrewcloud/coldbrew-cli/flags"
@@ -34,46 +35,18 @@ func (c *Command) Init(ka *kingpin.Application, globalFlags *flags.GlobalFlags)
 func (c *Command) Run() error {
 	c.awsClient = c.globalFlags.GetAWSClient()
 
-	appName := ""
-	clusterName := ""
-
 	// app configuration
-	configFilePath, err := c.globalFlags.GetConfigFile()
+	conf, appName, clusterName, err := commands.ResolveApp(c.globalFlags, c.commandFlags.AppName, c.commandFlags.ClusterName)
 	if err != nil {
 		return console.ExitWithError(err)
 	}
-	if utils.FileExists(configFilePath) {
-		configData, err := ioutil.ReadFile(configFilePath)
-		if err != nil {
-			return console.ExitWithErrorString("Failed to read configuration file [%s]: %s", configFilePath, err.Error())
-		}
-		conf, err := config.Load(configData, conv.S(c.globalFlags.ConfigFileFormat), core.DefaultAppName(configFilePath))
-		if err != nil {
-			return console.ExitWithError(err)
-		}
-
-		appName = conv.S(conf.Name)
-		clusterName = conv.S(conf.ClusterName)
-	}
-
-	// app/cluster name from CLI will override configuration file
-	if !utils.IsBlank(conv.S(c.commandFlags.AppName)) {
-		appName = conv.S(c.commandFlags.AppName)
-	}
-	if !utils.IsBlank(conv.S(c.commandFlags.ClusterName)) {
-		clusterName = conv.S(c.commandFlags.ClusterName)
-	}
-
-	if utils.IsBlank(appName) {
-		return console.ExitWithErrorString("App name is required.")
-	}
-	if utils.IsBlank(clusterName) {
-		return console.ExitWithErrorString("Cluster name is required.")
-	}
 
 	console.Info("Application")
 	console.DetailWithResource("Name", appName)
 	console.DetailWithResource("Cluster", clusterName)
+	if environment := conv.S(c.globalFlags.Environment); environment != "" {
+		console.DetailWithResource("Environment", environment)
+	}
 
 	// AWS networking
 	regionName, vpcID, err := c.globalFlags.GetAWSRegionAndVPCID()
@@ -107,8 +80,24 @@ func (c *Command) Run() error {
 		console.DetailWithResource("ECS Cluster", ecsClusterName)
 	}
 
-	// ECS Service
+	// ECS Service (blue/green deployment: live one)
 	ecsServiceName := core.DefaultECSServiceName(appName)
+	if commands.IsAppConfig(conf, appName, clusterName) && conv.B(conf.LoadBalancer.BlueGreen.Enabled) {
+		state, err := commands.RetrieveBlueGreenState(c.awsClient, conf)
+		if err != nil {
+			return console.ExitWithError(err)
+		}
+		ecsServiceName = state.Live.ECSServiceName
+
+		idleStatus := "(stopped)"
+		if state.Idle.ECSService == nil {
+			idleStatus = "(not found)"
+		} else if conv.I64(state.Idle.ECSService.DesiredCount) > 0 {
+			idleStatus = fmt.Sprintf("(running: %d/%d)", conv.I64(state.Idle.ECSService.RunningCount), conv.I64(state.Idle.ECSService.DesiredCount))
+		}
+		console.DetailWithResource("Blue/Green (live)", fmt.Sprintf("%s -> %s", state.Live.ECSServiceName, state.Live.ELBTargetGroupName))
+		console.DetailWithResourceNote("Blue/Green (idle)", fmt.Sprintf("%s -> %s", state.Idle.ECSServiceName, state.Idle.ELBTargetGroupName), idleStatus, false)
+	}
 	ecsService, err := c.awsClient.ECS().RetrieveService(ecsClusterName, ecsServiceName)
 	if err != nil {
 		return console.ExitWithErrorString("Failed to retrieve ECS Service [%s]: %s", ecsServiceName, err.Error())
@@ -118,6 +107,20 @@ func (c *Command) Run() error {
 		return nil // stop here
 	} else if conv.S(ecsService.Status) == "ACTIVE" {
 		console.DetailWithResource("ECS Service", ecsServiceName)
+		if dc := ecsService.DeploymentConfiguration; dc != nil {
+			console.DetailWithResource("Deployment (max/min healthy)",
+				fmt.Sprintf("%d%%/%d%%", conv.I64(dc.MaximumPercent), conv.I64(dc.MinimumHealthyPercent)))
+			console.DetailWithResource("Deployment circuit breaker", deploymentCircuitBreakerString(dc.DeploymentCircuitBreaker))
+		}
+		for _, s := range ecsService.PlacementStrategy {
+			console.DetailWithResource("Placement Strategy (type:field)", fmt.Sprintf("%s:%s", conv.S(s.Type), conv.S(s.Field)))
+		}
+		for _, pc := range ecsService.PlacementConstraints {
+			console.DetailWithResource("Placement Constraint (type:expression)", fmt.Sprintf("%s:%s", conv.S(pc.Type), conv.S(pc.Expression)))
+		}
+		if err := c.printAutoScaling(ecsClusterName, ecsServiceName); err != nil {
+			return console.ExitWithError(err)
+		}
 	} else {
 		console.DetailWithResourceNote("ECS Service", ecsServiceName, fmt.Sprintf("(%s)", conv.S(ecsService.Status)), true)
 		return nil // stop here
@@ -137,6 +140,28 @@ func (c *Command) Run() error {
 			fmt.Sprintf("%s:%d", conv.S(ecsTaskDefinition.Family), conv.I64(ecsTaskDefinition.Revision)))
 	}
 
+	if taskRoleARN := conv.S(ecsTaskDefinition.TaskRoleArn); taskRoleARN != "" {
+		console.DetailWithResource("IAM Task Role", taskRoleARN)
+	} else {
+		console.DetailWithResourceNote("IAM Task Role", "", "(none: containers use the container instance role)", true)
+	}
+
+	for _, v := range ecsTaskDefinition.Volumes {
+		switch {
+		case v.DockerVolumeConfiguration != nil:
+			console.DetailWithResource(fmt.Sprintf("Volume [%s]", conv.S(v.Name)),
+				fmt.Sprintf("docker (scope %s)", conv.S(v.DockerVolumeConfiguration.Scope)))
+		case v.EfsVolumeConfiguration != nil:
+			console.DetailWithResource(fmt.Sprintf("Volume [%s]", conv.S(v.Name)),
+				fmt.Sprintf("efs (%s)", conv.S(v.EfsVolumeConfiguration.FileSystemId)))
+		case v.Host != nil && conv.S(v.Host.SourcePath) != "":
+			console.DetailWithResource(fmt.Sprintf("Volume [%s]", conv.S(v.Name)),
+				fmt.Sprintf("host (%s)", conv.S(v.Host.SourcePath)))
+		default:
+			console.DetailWithResource(fmt.Sprintf("Volume [%s]", conv.S(v.Name)), "host (scratch)")
+		}
+	}
+
 	// Tasks count / status
 	isDeploying := false
 	if ecsService.Deployments != nil {
@@ -148,6 +173,12 @@ func (c *Command) Run() error {
 			}
 		}
 	}
+	for _, d := range ecsService.Deployments {
+		if conv.S(d.RolloutState) == _ecs.DeploymentRolloutStateFailed {
+			console.DetailWithResourceNote("Failed Deployment", aws.GetECSTaskDefinitionFamilyAndRevisionFromARN(conv.S(d.TaskDefinition)),
+				fmt.Sprintf("(%s)", conv.S(d.RolloutStateReason)), true)
+		}
+	}
 	if isDeploying {
 		console.DetailWithResourceNote("Tasks (current/desired/pending)", fmt.Sprintf("%d/%d/%d",
 			conv.I64(ecsService.RunningCount),
@@ -173,6 +204,9 @@ func (c *Command) Run() error {
 
 		memory := conv.I64(containerDefinition.Memory)
 		console.DetailWithResource("Memory", fmt.Sprintf("%dm", memory))
+		if memoryReservation := conv.I64(containerDefinition.MemoryReservation); memoryReservation > 0 {
+			console.DetailWithResource("Memory Reservation", fmt.Sprintf("%dm", memoryReservation))
+		}
 
 		for _, pm := range containerDefinition.PortMappings {
 			console.DetailWithResource("Port Mapping (protocol:container:host)", fmt.Sprintf("%s:%d:%d",
@@ -183,6 +217,57 @@ func (c *Command) Run() error {
 			console.DetailWithResource("Env", fmt.Sprintf("%s=%s",
 				conv.S(ev.Name), conv.S(ev.Value)))
 		}
+
+		// secrets: show references only
+		for _, secret := range containerDefinition.Secrets {
+			console.DetailWithResource("Secret", fmt.Sprintf("%s=%s",
+				conv.S(secret.Name), conv.S(secret.ValueFrom)))
+		}
+
+		console.DetailWithResource("Essential", fmt.Sprintf("%v", conv.B(containerDefinition.Essential)))
+
+		for _, d := range containerDefinition.DependsOn {
+			console.DetailWithResource("Depends On (container:condition)", fmt.Sprintf("%s:%s",
+				conv.S(d.ContainerName), conv.S(d.Condition)))
+		}
+
+		for _, link := range containerDefinition.Links {
+			console.DetailWithResource("Link", conv.S(link))
+		}
+
+		for _, mp := range containerDefinition.MountPoints {
+			mount := fmt.Sprintf("%s:%s", conv.S(mp.SourceVolume), conv.S(mp.ContainerPath))
+			if conv.B(mp.ReadOnly) {
+				mount += ":ro"
+			}
+			console.DetailWithResource("Mount (volume:path)", mount)
+		}
+
+		if len(containerDefinition.Command) > 0 {
+			console.DetailWithResource("Command", fmt.Sprintf("%q", _aws.StringValueSlice(containerDefinition.Command)))
+		}
+		if len(containerDefinition.EntryPoint) > 0 {
+			console.DetailWithResource("Entrypoint", fmt.Sprintf("%q", _aws.StringValueSlice(containerDefinition.EntryPoint)))
+		}
+		if workingDirectory := conv.S(containerDefinition.WorkingDirectory); workingDirectory != "" {
+			console.DetailWithResource("Working Directory", workingDirectory)
+		}
+		if user := conv.S(containerDefinition.User); user != "" {
+			console.DetailWithResource("User", user)
+		}
+		for _, u := range containerDefinition.Ulimits {
+			console.DetailWithResource("Ulimit (name=soft:hard)", fmt.Sprintf("%s=%d:%d",
+				conv.S(u.Name), conv.I64(u.SoftLimit), conv.I64(u.HardLimit)))
+		}
+		if stopTimeout := conv.I64(containerDefinition.StopTimeout); stopTimeout > 0 {
+			console.DetailWithResource("Stop Timeout", fmt.Sprintf("%ds", stopTimeout))
+		}
+
+		if h := containerDefinition.HealthCheck; h != nil {
+			console.DetailWithResource("Health Check", strings.Join(_aws.StringValueSlice(h.Command), " "))
+			console.DetailWithResource("Health Check (interval/timeout/retries/start period)", fmt.Sprintf("%ds/%ds/%d/%ds",
+				conv.I64(h.Interval), conv.I64(h.Timeout), conv.I64(h.Retries), conv.I64(h.StartPeriod)))
+		}
 	}
 
 	// Tasks
@@ -224,6 +309,18 @@ func (c *Command) Run() error {
 		console.DetailWithResource("Status (current/desired)", fmt.Sprintf("%s/%s",
 			conv.S(task.LastStatus), conv.S(task.DesiredStatus)))
 
+		if healthStatus := conv.S(task.HealthStatus); healthStatus != _ecs.HealthStatusUnknown && healthStatus != "" {
+			console.DetailWithResource("Health", healthStatus)
+		}
+
+		for _, container := range task.Containers {
+			status := conv.S(container.LastStatus)
+			if healthStatus := conv.S(container.HealthStatus); healthStatus != _ecs.HealthStatusUnknown && healthStatus != "" {
+				status = fmt.Sprintf("%s (%s)", status, healthStatus)
+			}
+			console.DetailWithResource(fmt.Sprintf("Container [%s]", conv.S(container.Name)), status)
+		}
+
 		for _, ci := range containerInstances {
 			if conv.S(task.ContainerInstanceArn) == conv.S(ci.ContainerInstanceArn) {
 				console.DetailWithResource("EC2 Instance ID", conv.S(ci.Ec2InstanceId))
@@ -295,3 +392,43 @@ func (c *Command) Run() error {
 
 	return nil
 }
+
+func (c *Command) printAutoScaling(ecsClusterName, ecsServiceName string) error {
+	scalableTarget, err := c.awsClient.ApplicationAutoScaling().RetrieveECSServiceScalableTarget(ecsClusterName, ecsServiceName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve auto scaling target for ECS Service [%s]: %s", ecsServiceName, err.Error())
+	}
+	if scalableTarget == nil {
+		console.DetailWithResource("Auto Scaling", "disabled")
+		return nil
+	}
+	console.DetailWithResource("Auto Scaling (min/max units)",
+		fmt.Sprintf("%d/%d", conv.I64(scalableTarget.MinCapacity), conv.I64(scalableTarget.MaxCapacity)))
+
+	policies, err := c.awsClient.ApplicationAutoScaling().RetrieveECSServiceScalingPolicies(ecsClusterName, ecsServiceName)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve auto scaling policies for ECS Service [%s]: %s", ecsServiceName, err.Error())
+	}
+	for _, p := range policies {
+		policyConfig := p.TargetTrackingScalingPolicyConfiguration
+		if policyConfig == nil || policyConfig.PredefinedMetricSpecification == nil {
+			console.DetailWithResource("Auto Scaling Policy", conv.S(p.PolicyName))
+			continue
+		}
+		console.DetailWithResource("Auto Scaling Policy", fmt.Sprintf("%s (%s: %.0f)", conv.S(p.PolicyName),
+			conv.S(policyConfig.PredefinedMetricSpecification.PredefinedMetricType), conv.F64(policyConfig.TargetValue)))
+	}
+
+	return nil
+}
+
+func deploymentCircuitBreakerString(circuitBreaker *_ecs.DeploymentCircuitBreaker) string {
+	switch {
+	case circuitBreaker == nil || !conv.B(circuitBreaker.Enable):
+		return "disabled"
+	case conv.B(circuitBreaker.Rollback):
+		return "enabled (rollback)"
+	default:
+		return "enabled"
+	}
+}
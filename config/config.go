@@ -1,17 +1,63 @@
 package config
 
 type Config struct {
-	Name         *string            `json:"name,omitempty" yaml:"name,omitempty"`
-	ClusterName  *string            `json:"cluster,omitempty" yaml:"cluster,omitempty"`
-	Port         *uint16            `json:"port,omitempty" yaml:"port,omitempty"`
-	CPU          *float64           `json:"cpu,omitempty" yaml:"cpu,omitempty"`
-	Memory       *string            `json:"memory,omitempty" yaml:"memory,omitempty"`
-	Units        *uint16            `json:"units,omitempty" yaml:"units,omitempty"`
-	Env          map[string]string  `json:"env,omitempty" yaml:"env,omitempty"`
-	LoadBalancer ConfigLoadBalancer `json:"load_balancer" yaml:"load_balancer"`
-	Logging      ConfigLogging      `json:"logging" yaml:"logging"`
-	AWS          ConfigAWS          `json:"aws" yaml:"aws"`
-	Docker       ConfigDocker       `json:"docker" yaml:"docker"`
+	Name              *string            `json:"name,omitempty" yaml:"name,omitempty"`
+	ClusterName       *string            `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	Port              *uint16            `json:"port,omitempty" yaml:"port,omitempty"`
+	Ports             []*ConfigPort      `json:"ports,omitempty" yaml:"ports,omitempty"`
+	CPU               *float64           `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Memory            *string            `json:"memory,omitempty" yaml:"memory,omitempty"`
+	MemoryReservation *string            `json:"memory_reservation,omitempty" yaml:"memory_reservation,omitempty"`
+	Units             *uint16            `json:"units,omitempty" yaml:"units,omitempty"`
+	HealthCheck       ConfigHealthCheck  `json:"health_check" yaml:"health_check"`
+	Runtime           ConfigRuntime      `json:"runtime" yaml:"runtime"`
+	Env               map[string]string  `json:"env,omitempty" yaml:"env,omitempty"`
+	EnvFile           []string           `json:"env_file,omitempty" yaml:"env_file,omitempty"`
+	LoadBalancer      ConfigLoadBalancer `json:"load_balancer" yaml:"load_balancer"`
+	Deployment        ConfigDeployment   `json:"deployment" yaml:"deployment"`
+	AutoScaling       ConfigAutoScaling  `json:"autoscaling" yaml:"autoscaling"`
+	Placement         ConfigPlacement    `json:"placement" yaml:"placement"`
+	Volumes           []*ConfigVolume    `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	Mounts            []*ConfigMount     `json:"mounts,omitempty" yaml:"mounts,omitempty"`
+	IAM               ConfigIAM          `json:"iam" yaml:"iam"`
+	Schedules         []*ConfigSchedule  `json:"schedules,omitempty" yaml:"schedules,omitempty"`
+	Logging           ConfigLogging      `json:"logging" yaml:"logging"`
+	AWS               ConfigAWS          `json:"aws" yaml:"aws"`
+	Docker            ConfigDocker       `json:"docker" yaml:"docker"`
+	Containers        []*ConfigContainer `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Environments      map[string]*Config `json:"environments,omitempty" yaml:"environments,omitempty"`
+}
+
+// ConfigHealthCheck is the container health check: command is run in the container ("CMD" or "CMD-SHELL" followed
+// by the command), and the container becomes unhealthy after "retries" consecutive failures. Health check is
+// disabled if command is empty.
+type ConfigHealthCheck struct {
+	Command     []string `json:"command,omitempty" yaml:"command,omitempty"`
+	Interval    *string  `json:"interval,omitempty" yaml:"interval,omitempty"`
+	Timeout     *string  `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Retries     *uint16  `json:"retries,omitempty" yaml:"retries,omitempty"`
+	StartPeriod *string  `json:"start_period,omitempty" yaml:"start_period,omitempty"`
+}
+
+// ConfigRuntime has Docker runtime options of a container. Command, entrypoint, working directory, and user
+// override the image's defaults. Extra hosts are hostname to IP address. Stop timeout is how long to wait
+// for the container to exit on its own before it's killed.
+type ConfigRuntime struct {
+	Command          []string          `json:"command,omitempty" yaml:"command,omitempty"`
+	EntryPoint       []string          `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
+	WorkingDirectory *string           `json:"working_directory,omitempty" yaml:"working_directory,omitempty"`
+	User             *string           `json:"user,omitempty" yaml:"user,omitempty"`
+	Ulimits          []*ConfigUlimit   `json:"ulimits,omitempty" yaml:"ulimits,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	ExtraHosts       map[string]string `json:"extra_hosts,omitempty" yaml:"extra_hosts,omitempty"`
+	DNSServers       []string          `json:"dns_servers,omitempty" yaml:"dns_servers,omitempty"`
+	StopTimeout      *string           `json:"stop_timeout,omitempty" yaml:"stop_timeout,omitempty"`
+}
+
+type ConfigUlimit struct {
+	Name *string `json:"name,omitempty" yaml:"name,omitempty"`
+	Soft *uint64 `json:"soft,omitempty" yaml:"soft,omitempty"`
+	Hard *uint64 `json:"hard,omitempty" yaml:"hard,omitempty"`
 }
 
 type ConfigLoadBalancer struct {
@@ -19,6 +65,7 @@ type ConfigLoadBalancer struct {
 	Port        *uint16                       `json:"port,omitempty" yaml:"port,omitempty"`
 	HTTPSPort   *uint16                       `json:"https_port,omitempty" yaml:"https_port,omitempty"`
 	HealthCheck ConfigLoadBalancerHealthCheck `json:"health_check,omitempty" yaml:"health_check,omitempty"`
+	BlueGreen   ConfigLoadBalancerBlueGreen   `json:"blue_green,omitempty" yaml:"blue_green,omitempty"`
 }
 
 type ConfigLoadBalancerHealthCheck struct {
@@ -30,19 +77,158 @@ type ConfigLoadBalancerHealthCheck struct {
 	UnhealthyLimit *uint16 `json:"unhealthy_limit,omitempty" yaml:"unhealthy_limit,omitempty"`
 }
 
+// ConfigLoadBalancerBlueGreen enables blue/green deployments: new versions are deployed to the idle ECS Service
+// and ELB Target Group, and the listeners are switched to it once all targets are healthy. The previous service
+// keeps running for the bake time so the listeners can be switched back instantly.
+type ConfigLoadBalancerBlueGreen struct {
+	Enabled  *bool   `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	BakeTime *string `json:"bake_time,omitempty" yaml:"bake_time,omitempty"`
+}
+
+// ConfigDeployment controls ECS Service deployments: percentages of desired count that can run (max) and must stay
+// healthy (min) during deployments, and ECS deployment circuit breaker.
+type ConfigDeployment struct {
+	MaxPercent        *uint16                        `json:"max_percent,omitempty" yaml:"max_percent,omitempty"`
+	MinHealthyPercent *uint16                        `json:"min_healthy_percent,omitempty" yaml:"min_healthy_percent,omitempty"`
+	CircuitBreaker    ConfigDeploymentCircuitBreaker `json:"circuit_breaker" yaml:"circuit_breaker"`
+}
+
+type ConfigDeploymentCircuitBreaker struct {
+	Enabled  *bool `json:"enabled" yaml:"enabled"`
+	Rollback *bool `json:"rollback" yaml:"rollback"`
+}
+
+// ConfigAutoScaling enables target tracking auto scaling of the ECS Service between min and max units.
+// Each non-zero target creates a policy: average CPU or memory utilization (percent) of the app's tasks,
+// or average number of requests per ELB target (per minute).
+type ConfigAutoScaling struct {
+	Enabled        *bool   `json:"enabled" yaml:"enabled"`
+	MinUnits       *uint16 `json:"min_units,omitempty" yaml:"min_units,omitempty"`
+	MaxUnits       *uint16 `json:"max_units,omitempty" yaml:"max_units,omitempty"`
+	CPUTarget      *uint16 `json:"cpu_target,omitempty" yaml:"cpu_target,omitempty"`
+	MemoryTarget   *uint16 `json:"memory_target,omitempty" yaml:"memory_target,omitempty"`
+	RequestsTarget *uint16 `json:"requests_target,omitempty" yaml:"requests_target,omitempty"`
+}
+
+// ConfigPlacement is the task placement of the ECS Service: strategies (e.g. "spread" by availability zone,
+// "binpack" by memory) are evaluated in order, and constraints (e.g. "distinctInstance", "memberOf") must be
+// satisfied by all tasks. Placement is set when the ECS Service is created.
+type ConfigPlacement struct {
+	Strategy    []*ConfigPlacementStrategy   `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+	Constraints []*ConfigPlacementConstraint `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+}
+
+type ConfigPlacementStrategy struct {
+	Type  *string `json:"type,omitempty" yaml:"type,omitempty"`
+	Field *string `json:"field,omitempty" yaml:"field,omitempty"`
+}
+
+type ConfigPlacementConstraint struct {
+	Type       *string `json:"type,omitempty" yaml:"type,omitempty"`
+	Expression *string `json:"expression,omitempty" yaml:"expression,omitempty"`
+}
+
+// ConfigVolume is a data volume of the task that the app container and the additional containers can mount.
+// Type is "host" (host path on the container instance, or a scratch volume if path is empty), "docker"
+// (Docker named volume), or "efs" (EFS file system).
+type ConfigVolume struct {
+	Name     *string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Type     *string            `json:"type,omitempty" yaml:"type,omitempty"`
+	HostPath *string            `json:"host_path,omitempty" yaml:"host_path,omitempty"`
+	Docker   ConfigVolumeDocker `json:"docker" yaml:"docker"`
+	EFS      ConfigVolumeEFS    `json:"efs" yaml:"efs"`
+}
+
+type ConfigVolumeDocker struct {
+	Driver        *string           `json:"driver,omitempty" yaml:"driver,omitempty"`
+	DriverOptions map[string]string `json:"driver_options,omitempty" yaml:"driver_options,omitempty"`
+	Scope         *string           `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Autoprovision *bool             `json:"autoprovision,omitempty" yaml:"autoprovision,omitempty"`
+}
+
+type ConfigVolumeEFS struct {
+	FileSystemID      *string `json:"file_system_id,omitempty" yaml:"file_system_id,omitempty"`
+	RootDirectory     *string `json:"root_directory,omitempty" yaml:"root_directory,omitempty"`
+	AccessPointID     *string `json:"access_point_id,omitempty" yaml:"access_point_id,omitempty"`
+	TransitEncryption *bool   `json:"transit_encryption,omitempty" yaml:"transit_encryption,omitempty"`
+	IAM               *bool   `json:"iam,omitempty" yaml:"iam,omitempty"`
+}
+
+// ConfigMount mounts the volume at path in the container.
+type ConfigMount struct {
+	Volume   *string `json:"volume,omitempty" yaml:"volume,omitempty"`
+	Path     *string `json:"path,omitempty" yaml:"path,omitempty"`
+	ReadOnly *bool   `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+}
+
+// ConfigIAM is the permissions of the app's IAM task role: managed policies (ARNs) are attached to the role,
+// and the statements become its inline policy. The containers run with no permissions if both are empty.
+type ConfigIAM struct {
+	ManagedPolicies []string              `json:"managed_policies,omitempty" yaml:"managed_policies,omitempty"`
+	Statements      []*ConfigIAMStatement `json:"statements,omitempty" yaml:"statements,omitempty"`
+}
+
+type ConfigIAMStatement struct {
+	Effect    *string  `json:"effect,omitempty" yaml:"effect,omitempty"`
+	Actions   []string `json:"actions,omitempty" yaml:"actions,omitempty"`
+	Resources []string `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// ConfigSchedule runs tasks of the app on a schedule: expression is a CloudWatch Events schedule expression
+// ("cron(0 3 * * ? *)" or "rate(1 hour)"), and command (if not empty) overrides the command of the app container.
+type ConfigSchedule struct {
+	Name       *string  `json:"name,omitempty" yaml:"name,omitempty"`
+	Expression *string  `json:"expression,omitempty" yaml:"expression,omitempty"`
+	Command    []string `json:"command,omitempty" yaml:"command,omitempty"`
+	Count      *uint16  `json:"count,omitempty" yaml:"count,omitempty"`
+}
+
 type ConfigLogging struct {
 	Driver  *string           `json:"driver,omitempty" yaml:"driver,omitempty"`
 	Options map[string]string `json:"options" yaml:"options"`
 }
 
 type ConfigAWS struct {
-	ELBLoadBalancerName  *string `json:"elb_name,omitempty" yaml:"elb_name,omitempty"`
-	ELBTargetGroupName   *string `json:"elb_target_group_name,omitempty" yaml:"elb_target_group_name,omitempty"`
-	ELBSecurityGroupName *string `json:"elb_security_group_name,omitempty" yaml:"elb_security_group_name,omitempty"`
-	ELBCertificateARN    *string `json:"elb_certificate_arn,omitempty" yaml:"elb_certificate_arn,omitempty"`
-	ECRRepositoryName    *string `json:"ecr_repo_name,omitempty" yaml:"ecr_repo_name,omitempty"`
+	ELBLoadBalancerName     *string `json:"elb_name,omitempty" yaml:"elb_name,omitempty"`
+	ELBTargetGroupName      *string `json:"elb_target_group_name,omitempty" yaml:"elb_target_group_name,omitempty"`
+	ELBGreenTargetGroupName *string `json:"elb_green_target_group_name,omitempty" yaml:"elb_green_target_group_name,omitempty"`
+	ELBSecurityGroupName    *string `json:"elb_security_group_name,omitempty" yaml:"elb_security_group_name,omitempty"`
+	ELBCertificateARN       *string `json:"elb_certificate_arn,omitempty" yaml:"elb_certificate_arn,omitempty"`
+	ECRRepositoryName       *string `json:"ecr_repo_name,omitempty" yaml:"ecr_repo_name,omitempty"`
 }
 
 type ConfigDocker struct {
-	Bin *string `json:"bin,omitempty" yaml:"bin,omitempty"`
+	Bin      *string `json:"bin,omitempty" yaml:"bin,omitempty"`
+	ImageTag *string `json:"image_tag,omitempty" yaml:"image_tag,omitempty"`
+}
+
+// ConfigContainer is an additional (sidecar) container that runs in the same task as the app container.
+type ConfigContainer struct {
+	Name              *string                      `json:"name,omitempty" yaml:"name,omitempty"`
+	Image             *string                      `json:"image,omitempty" yaml:"image,omitempty"`
+	CPU               *float64                     `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Memory            *string                      `json:"memory,omitempty" yaml:"memory,omitempty"`
+	MemoryReservation *string                      `json:"memory_reservation,omitempty" yaml:"memory_reservation,omitempty"`
+	Env               map[string]string            `json:"env,omitempty" yaml:"env,omitempty"`
+	Ports             []*ConfigPort                `json:"ports,omitempty" yaml:"ports,omitempty"`
+	Essential         *bool                        `json:"essential,omitempty" yaml:"essential,omitempty"`
+	DependsOn         []*ConfigContainerDependency `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Links             []string                     `json:"links,omitempty" yaml:"links,omitempty"`
+	Mounts            []*ConfigMount               `json:"mounts,omitempty" yaml:"mounts,omitempty"`
+	HealthCheck       ConfigHealthCheck            `json:"health_check" yaml:"health_check"`
+	Runtime           ConfigRuntime                `json:"runtime" yaml:"runtime"`
+}
+
+// ConfigPort is a port mapping of a container. Host port 0 is assigned dynamically. LoadBalanced marks the
+// app container port that the ELB Target Group routes to (not used for the additional containers).
+type ConfigPort struct {
+	ContainerPort *uint16 `json:"container_port,omitempty" yaml:"container_port,omitempty"`
+	HostPort      *uint16 `json:"host_port,omitempty" yaml:"host_port,omitempty"`
+	Protocol      *string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	LoadBalanced  *bool   `json:"load_balanced,omitempty" yaml:"load_balanced,omitempty"`
+}
+
+type ConfigContainerDependency struct {
+	Container *string `json:"container,omitempty" yaml:"container,omitempty"`
+	Condition *string `json:"condition,omitempty" yaml:"condition,omitempty"`
 }
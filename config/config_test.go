@@ -8,8 +8,32 @@ cluster: cluster1
 port: 8080
 cpu: 1.0
 memory: 200m
+memory_reservation: 100m
 units: 4
 
+health_check:
+  command: ["CMD-SHELL", "curl -f http://localhost:8080/ping || exit 1"]
+  interval: 10s
+  timeout: 3s
+  retries: 5
+  start_period: 1m
+
+runtime:
+  command: ["./echo", "--verbose"]
+  entrypoint: ["/bin/sh", "-c"]
+  working_directory: /app
+  user: "1000:1000"
+  ulimits:
+    - name: nofile
+      soft: 65536
+      hard: 65536
+  labels:
+    team: platform
+  extra_hosts:
+    db.internal: 10.0.0.10
+  dns_servers: ["10.0.0.2"]
+  stop_timeout: 30s
+
 env:
   key1: value1
   key2: value2
@@ -27,6 +51,81 @@ load_balancer:
     healthy_limit: 5
     unhealthy_limit: 2
 
+  blue_green:
+    enabled: true
+    bake_time: 10m
+
+deployment:
+  max_percent: 150
+  min_healthy_percent: 100
+  circuit_breaker:
+    enabled: true
+    rollback: true
+
+autoscaling:
+  enabled: true
+  min_units: 2
+  max_units: 10
+  cpu_target: 60
+  memory_target: 0
+  requests_target: 1000
+
+placement:
+  strategy:
+    - type: spread
+      field: attribute:ecs.availability-zone
+    - type: binpack
+      field: memory
+  constraints:
+    - type: distinctInstance
+    - type: memberOf
+      expression: "attribute:ecs.instance-type =~ t2.*"
+
+volumes:
+  - name: scratch
+    type: host
+  - name: cache
+    type: docker
+    docker:
+      driver: local
+      driver_options:
+        type: tmpfs
+        device: tmpfs
+      scope: shared
+      autoprovision: true
+  - name: data
+    type: efs
+    efs:
+      file_system_id: fs-12345678
+      access_point_id: fsap-1234567890abcdef0
+      transit_encryption: true
+      iam: false
+
+mounts:
+  - volume: scratch
+    path: /tmp/scratch
+    read_only: false
+  - volume: data
+    path: /data
+    read_only: true
+
+iam:
+  managed_policies:
+    - arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess
+  statements:
+    - effect: Allow
+      actions: ["sqs:SendMessage", "sqs:ReceiveMessage"]
+      resources: ["arn:aws:sqs:us-west-2:123456789012:echo-queue"]
+
+schedules:
+  - name: nightly-report
+    expression: cron(0 3 * * ? *)
+    command: ["./report", "--daily"]
+    count: 1
+  - name: cleanup
+    expression: rate(6 hours)
+    count: 2
+
 logging:
   driver: json-file
   options:
@@ -36,12 +135,43 @@ logging:
 aws:
   elb_name: echo-lb
   elb_target_group_name: echo-target
+  elb_green_target_group_name: echo-target-green
   elb_security_group_name: echo-lb-sg
   elb_certificate_arn: arn:aws:acm:us-west-2:aws-account-id:certificate/certificiate-identifier
   ecr_repo_name: echo-repo
 
 docker:
   bin: "/usr/local/bin/docker"
+  image_tag: "v1-{short_sha}{dirty}"
+
+containers:
+  - name: proxy
+    image: nginx:1.13
+    cpu: 0.25
+    memory: 128m
+    memory_reservation: 64m
+    env:
+      key3: value3
+    ports:
+      - container_port: 80
+        host_port: 0
+        protocol: tcp
+    essential: true
+    depends_on:
+      - container: echo
+        condition: START
+    links:
+      - echo
+    mounts:
+      - volume: cache
+        path: /var/cache/nginx
+        read_only: false
+    health_check:
+      command: ["CMD", "nginx", "-t"]
+      interval: 1m
+      timeout: 10s
+      retries: 2
+      start_period: 0s
 `
 
 const refConfigJSON = `
@@ -51,7 +181,28 @@ const refConfigJSON = `
 	"port": 8080,
 	"cpu": 1.0,
 	"memory": "200m",
+	"memory_reservation": "100m",
 	"units": 4,
+	"health_check": {
+		"command": ["CMD-SHELL", "curl -f http://localhost:8080/ping || exit 1"],
+		"interval": "10s",
+		"timeout": "3s",
+		"retries": 5,
+		"start_period": "1m"
+	},
+	"runtime": {
+		"command": ["./echo", "--verbose"],
+		"entrypoint": ["/bin/sh", "-c"],
+		"working_directory": "/app",
+		"user": "1000:1000",
+		"ulimits": [
+			{"name": "nofile", "soft": 65536, "hard": 65536}
+		],
+		"labels": {"team": "platform"},
+		"extra_hosts": {"db.internal": "10.0.0.10"},
+		"dns_servers": ["10.0.0.2"],
+		"stop_timeout": "30s"
+	},
 	"env": {
 		"key1": "value1",
 		"key2": "value2"
@@ -67,8 +218,79 @@ const refConfigJSON = `
 			"timeout": "5s",
 			"healthy_limit": 5,
 			"unhealthy_limit": 2
+		},
+		"blue_green": {
+			"enabled": true,
+			"bake_time": "10m"
+		}
+	},
+	"deployment": {
+		"max_percent": 150,
+		"min_healthy_percent": 100,
+		"circuit_breaker": {
+			"enabled": true,
+			"rollback": true
+		}
+	},
+	"autoscaling": {
+		"enabled": true,
+		"min_units": 2,
+		"max_units": 10,
+		"cpu_target": 60,
+		"memory_target": 0,
+		"requests_target": 1000
+	},
+	"placement": {
+		"strategy": [
+			{"type": "spread", "field": "attribute:ecs.availability-zone"},
+			{"type": "binpack", "field": "memory"}
+		],
+		"constraints": [
+			{"type": "distinctInstance"},
+			{"type": "memberOf", "expression": "attribute:ecs.instance-type =~ t2.*"}
+		]
+	},
+	"volumes": [
+		{"name": "scratch", "type": "host"},
+		{
+			"name": "cache",
+			"type": "docker",
+			"docker": {
+				"driver": "local",
+				"driver_options": {"type": "tmpfs", "device": "tmpfs"},
+				"scope": "shared",
+				"autoprovision": true
+			}
+		},
+		{
+			"name": "data",
+			"type": "efs",
+			"efs": {
+				"file_system_id": "fs-12345678",
+				"access_point_id": "fsap-1234567890abcdef0",
+				"transit_encryption": true,
+				"iam": false
+			}
 		}
+	],
+	"mounts": [
+		{"volume": "scratch", "path": "/tmp/scratch", "read_only": false},
+		{"volume": "data", "path": "/data", "read_only": true}
+	],
+	"iam": {
+		"managed_policies": ["arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"],
+		"statements": [
+			{
+				"effect": "Allow",
+				"actions": ["sqs:SendMessage", "sqs:ReceiveMessage"],
+				"resources": ["arn:aws:sqs:us-west-2:123456789012:echo-queue"]
+			}
+		]
 	},
+	"schedules": [
+		{"name": "nightly-report", "expression": "cron(0 3 * * ? *)", "command": ["./report", "--daily"], "count": 1},
+		{"name": "cleanup", "expression": "rate(6 hours)", "count": 2}
+	],
 	"logging": {
 	    "driver": "json-file",
 	    "options": {
@@ -79,22 +301,82 @@ const refConfigJSON = `
 	"aws": {
 		"elb_name": "echo-lb",
 		"elb_target_group_name": "echo-target",
+		"elb_green_target_group_name": "echo-target-green",
 		"elb_security_group_name": "echo-lb-sg",
 		"elb_certificate_arn": "arn:aws:acm:us-west-2:aws-account-id:certificate/certificiate-identifier",
 		"ecr_repo_name": "echo-repo"
 	},
 	"docker": {
-		"bin": "/usr/local/bin/docker"
-	}
+		"bin": "/usr/local/bin/docker",
+		"image_tag": "v1-{short_sha}{dirty}"
+	},
+	"containers": [
+		{
+			"name": "proxy",
+			"image": "nginx:1.13",
+			"cpu": 0.25,
+			"memory": "128m",
+			"memory_reservation": "64m",
+			"env": {
+				"key3": "value3"
+			},
+			"ports": [
+				{
+					"container_port": 80,
+					"host_port": 0,
+					"protocol": "tcp"
+				}
+			],
+			"essential": true,
+			"depends_on": [
+				{
+					"container": "echo",
+					"condition": "START"
+				}
+			],
+			"links": ["echo"],
+			"mounts": [
+				{"volume": "cache", "path": "/var/cache/nginx", "read_only": false}
+			],
+			"health_check": {
+				"command": ["CMD", "nginx", "-t"],
+				"interval": "1m",
+				"timeout": "10s",
+				"retries": 2,
+				"start_period": "0s"
+			}
+		}
+	]
 }`
 
 var refConfig = &Config{
-	Name:        conv.SP("echo"),
-	ClusterName: conv.SP("cluster1"),
-	Port:        conv.U16P(8080),
-	CPU:         conv.F64P(1.0),
-	Memory:      conv.SP("200m"),
-	Units:       conv.U16P(4),
+	Name:              conv.SP("echo"),
+	ClusterName:       conv.SP("cluster1"),
+	Port:              conv.U16P(8080),
+	CPU:               conv.F64P(1.0),
+	Memory:            conv.SP("200m"),
+	MemoryReservation: conv.SP("100m"),
+	Units:             conv.U16P(4),
+	HealthCheck: ConfigHealthCheck{
+		Command:     []string{"CMD-SHELL", "curl -f http://localhost:8080/ping || exit 1"},
+		Interval:    conv.SP("10s"),
+		Timeout:     conv.SP("3s"),
+		Retries:     conv.U16P(5),
+		StartPeriod: conv.SP("1m"),
+	},
+	Runtime: ConfigRuntime{
+		Command:          []string{"./echo", "--verbose"},
+		EntryPoint:       []string{"/bin/sh", "-c"},
+		WorkingDirectory: conv.SP("/app"),
+		User:             conv.SP("1000:1000"),
+		Ulimits: []*ConfigUlimit{
+			{Name: conv.SP("nofile"), Soft: conv.U64P(65536), Hard: conv.U64P(65536)},
+		},
+		Labels:      map[string]string{"team": "platform"},
+		ExtraHosts:  map[string]string{"db.internal": "10.0.0.10"},
+		DNSServers:  []string{"10.0.0.2"},
+		StopTimeout: conv.SP("30s"),
+	},
 	Env: map[string]string{
 		"key1": "value1",
 		"key2": "value2",
@@ -111,6 +393,86 @@ var refConfig = &Config{
 			HealthyLimit:   conv.U16P(5),
 			UnhealthyLimit: conv.U16P(2),
 		},
+		BlueGreen: ConfigLoadBalancerBlueGreen{
+			Enabled:  conv.BP(true),
+			BakeTime: conv.SP("10m"),
+		},
+	},
+	Deployment: ConfigDeployment{
+		MaxPercent:        conv.U16P(150),
+		MinHealthyPercent: conv.U16P(100),
+		CircuitBreaker: ConfigDeploymentCircuitBreaker{
+			Enabled:  conv.BP(true),
+			Rollback: conv.BP(true),
+		},
+	},
+	AutoScaling: ConfigAutoScaling{
+		Enabled:        conv.BP(true),
+		MinUnits:       conv.U16P(2),
+		MaxUnits:       conv.U16P(10),
+		CPUTarget:      conv.U16P(60),
+		MemoryTarget:   conv.U16P(0),
+		RequestsTarget: conv.U16P(1000),
+	},
+	Placement: ConfigPlacement{
+		Strategy: []*ConfigPlacementStrategy{
+			{Type: conv.SP("spread"), Field: conv.SP("attribute:ecs.availability-zone")},
+			{Type: conv.SP("binpack"), Field: conv.SP("memory")},
+		},
+		Constraints: []*ConfigPlacementConstraint{
+			{Type: conv.SP("distinctInstance")},
+			{Type: conv.SP("memberOf"), Expression: conv.SP("attribute:ecs.instance-type =~ t2.*")},
+		},
+	},
+	Volumes: []*ConfigVolume{
+		{Name: conv.SP("scratch"), Type: conv.SP("host")},
+		{
+			Name: conv.SP("cache"),
+			Type: conv.SP("docker"),
+			Docker: ConfigVolumeDocker{
+				Driver:        conv.SP("local"),
+				DriverOptions: map[string]string{"type": "tmpfs", "device": "tmpfs"},
+				Scope:         conv.SP("shared"),
+				Autoprovision: conv.BP(true),
+			},
+		},
+		{
+			Name: conv.SP("data"),
+			Type: conv.SP("efs"),
+			EFS: ConfigVolumeEFS{
+				FileSystemID:      conv.SP("fs-12345678"),
+				AccessPointID:     conv.SP("fsap-1234567890abcdef0"),
+				TransitEncryption: conv.BP(true),
+				IAM:               conv.BP(false),
+			},
+		},
+	},
+	Mounts: []*ConfigMount{
+		{Volume: conv.SP("scratch"), Path: conv.SP("/tmp/scratch"), ReadOnly: conv.BP(false)},
+		{Volume: conv.SP("data"), Path: conv.SP("/data"), ReadOnly: conv.BP(true)},
+	},
+	IAM: ConfigIAM{
+		ManagedPolicies: []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"},
+		Statements: []*ConfigIAMStatement{
+			{
+				Effect:    conv.SP("Allow"),
+				Actions:   []string{"sqs:SendMessage", "sqs:ReceiveMessage"},
+				Resources: []string{"arn:aws:sqs:us-west-2:123456789012:echo-queue"},
+			},
+		},
+	},
+	Schedules: []*ConfigSchedule{
+		{
+			Name:       conv.SP("nightly-report"),
+			Expression: conv.SP("cron(0 3 * * ? *)"),
+			Command:    []string{"./report", "--daily"},
+			Count:      conv.U16P(1),
+		},
+		{
+			Name:       conv.SP("cleanup"),
+			Expression: conv.SP("rate(6 hours)"),
+			Count:      conv.U16P(2),
+		},
 	},
 	Logging: ConfigLogging{
 		Driver: conv.SP("json-file"),
@@ -120,14 +482,53 @@ var refConfig = &Config{
 		},
 	},
 	AWS: ConfigAWS{
-		ELBLoadBalancerName:  conv.SP("echo-lb"),
-		ELBTargetGroupName:   conv.SP("echo-target"),
-		ELBSecurityGroupName: conv.SP("echo-lb-sg"),
-		ELBCertificateARN:    conv.SP("arn:aws:acm:us-west-2:aws-account-id:certificate/certificiate-identifier"),
-		ECRRepositoryName:    conv.SP("echo-repo"),
+		ELBLoadBalancerName:     conv.SP("echo-lb"),
+		ELBTargetGroupName:      conv.SP("echo-target"),
+		ELBGreenTargetGroupName: conv.SP("echo-target-green"),
+		ELBSecurityGroupName:    conv.SP("echo-lb-sg"),
+		ELBCertificateARN:       conv.SP("arn:aws:acm:us-west-2:aws-account-id:certificate/certificiate-identifier"),
+		ECRRepositoryName:       conv.SP("echo-repo"),
 	},
 	Docker: ConfigDocker{
-		Bin: conv.SP("/usr/local/bin/docker"),
+		Bin:      conv.SP("/usr/local/bin/docker"),
+		ImageTag: conv.SP("v1-{short_sha}{dirty}"),
+	},
+	Containers: []*ConfigContainer{
+		{
+			Name:              conv.SP("proxy"),
+			Image:             conv.SP("nginx:1.13"),
+			CPU:               conv.F64P(0.25),
+			Memory:            conv.SP("128m"),
+			MemoryReservation: conv.SP("64m"),
+			Env: map[string]string{
+				"key3": "value3",
+			},
+			Ports: []*ConfigPort{
+				{
+					ContainerPort: conv.U16P(80),
+					HostPort:      conv.U16P(0),
+					Protocol:      conv.SP("tcp"),
+				},
+			},
+			Essential: conv.BP(true),
+			DependsOn: []*ConfigContainerDependency{
+				{
+					Container: conv.SP("echo"),
+					Condition: conv.SP("START"),
+				},
+			},
+			Links: []string{"echo"},
+			Mounts: []*ConfigMount{
+				{Volume: conv.SP("cache"), Path: conv.SP("/var/cache/nginx"), ReadOnly: conv.BP(false)},
+			},
+			HealthCheck: ConfigHealthCheck{
+				Command:     []string{"CMD", "nginx", "-t"},
+				Interval:    conv.SP("1m"),
+				Timeout:     conv.SP("10s"),
+				Retries:     conv.U16P(2),
+				StartPeriod: conv.SP("0s"),
+			},
+		},
 	},
 }
 
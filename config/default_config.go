@@ -18,6 +18,9 @@ func DefaultConfig(appName string) *Config {
 	// Environment variables
 	conf.Env = make(map[string]string)
 
+	// container health check (disabled unless command is set)
+	conf.HealthCheck = defaultHealthCheck()
+
 	// load balancer
 	{
 		conf.LoadBalancer.Enabled = conv.BP(false)
@@ -30,6 +33,28 @@ func DefaultConfig(appName string) *Config {
 		conf.LoadBalancer.HealthCheck.Timeout = conv.SP("10s")
 		conf.LoadBalancer.HealthCheck.HealthyLimit = conv.U16P(3)
 		conf.LoadBalancer.HealthCheck.UnhealthyLimit = conv.U16P(3)
+
+		// blue/green deployment
+		conf.LoadBalancer.BlueGreen.Enabled = conv.BP(false)
+		conf.LoadBalancer.BlueGreen.BakeTime = conv.SP("5m")
+	}
+
+	// deployment
+	{
+		conf.Deployment.MaxPercent = conv.U16P(200)
+		conf.Deployment.MinHealthyPercent = conv.U16P(50)
+		conf.Deployment.CircuitBreaker.Enabled = conv.BP(false)
+		conf.Deployment.CircuitBreaker.Rollback = conv.BP(false)
+	}
+
+	// auto scaling
+	{
+		conf.AutoScaling.Enabled = conv.BP(false)
+		conf.AutoScaling.MinUnits = conv.U16P(1)
+		conf.AutoScaling.MaxUnits = conv.U16P(4)
+		conf.AutoScaling.CPUTarget = conv.U16P(0)
+		conf.AutoScaling.MemoryTarget = conv.U16P(0)
+		conf.AutoScaling.RequestsTarget = conv.U16P(0)
 	}
 
 	// logging
@@ -58,6 +83,15 @@ func DefaultConfig(appName string) *Config {
 		}
 		conf.AWS.ELBTargetGroupName = conv.SP(elbLoadBalancerTargetGroupName)
 
+		// ELB target group name for blue/green deployment: cannot exceed 32 chars
+		elbGreenTargetGroupName := ""
+		if len(appName) > 23 {
+			elbGreenTargetGroupName = core.DefaultELBGreenTargetGroupName(appName[:23])
+		} else {
+			elbGreenTargetGroupName = core.DefaultELBGreenTargetGroupName(appName)
+		}
+		conf.AWS.ELBGreenTargetGroupName = conv.SP(elbGreenTargetGroupName)
+
 		// ELB security group
 		elbSecurityGroupName := ""
 		if len(appName) > 25 {
@@ -73,6 +107,17 @@ func DefaultConfig(appName string) *Config {
 
 	// Docker
 	conf.Docker.Bin = conv.SP("docker")
+	conf.Docker.ImageTag = conv.SP(core.DefaultDockerImageTag)
 
 	return conf
 }
+
+// defaultHealthCheck is also used for the additional containers.
+func defaultHealthCheck() ConfigHealthCheck {
+	return ConfigHealthCheck{
+		Interval:    conv.SP("30s"),
+		Timeout:     conv.SP("5s"),
+		Retries:     conv.U16P(3),
+		StartPeriod: conv.SP("0s"),
+	}
+}
@@ -20,6 +20,7 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Len(t, conv.S(defConf.AWS.ELBLoadBalancerName), 32)
 	assert.Len(t, conv.S(defConf.AWS.ELBTargetGroupName), 32)
+	assert.Len(t, conv.S(defConf.AWS.ELBGreenTargetGroupName), 32)
 
 	// app name's too long
 	defConf = DefaultConfig("123456789012345678901234567890123")
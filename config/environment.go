@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ApplyEnvironment overlays the named environment on top of the base configuration. Fields that are set in the
+// environment take precedence over the base configuration (same field-level semantics as Defaults), env variables
+// are merged with environment values winning, and containers are replaced only if the environment defines them.
+func (c *Config) ApplyEnvironment(name string) error {
+	overlay, ok := c.Environments[name]
+	if !ok || overlay == nil {
+		return fmt.Errorf("Environment [%s] was not found in the configuration. (available: %v)", name, c.EnvironmentNames())
+	}
+	if len(overlay.Environments) > 0 {
+		return fmt.Errorf("Environment [%s] cannot define nested environments.", name)
+	}
+	if len(overlay.EnvFile) > 0 {
+		return fmt.Errorf("Environment [%s] cannot define env_file: use env_file of the base configuration.", name)
+	}
+
+	base := *c
+	merged := *overlay
+	merged.Env = nil
+	merged.Defaults(&base)
+	for k, v := range overlay.Env {
+		merged.Env[k] = v
+	}
+	if merged.Containers == nil {
+		merged.Containers = base.Containers
+	}
+	merged.EnvFile = base.EnvFile
+	merged.Environments = base.Environments
+
+	*c = merged
+	return nil
+}
+
+// EnvironmentNames returns the sorted names of all environments defined in the configuration.
+func (c *Config) EnvironmentNames() []string {
+	names := []string{}
+	for name := range c.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
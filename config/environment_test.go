@@ -0,0 +1,89 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/coldbrewcloud/coldbrew-cli/flags"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"github.com/stretchr/testify/assert"
+)
+
+const testEnvironmentsConfigYAML = `
+name: echo
+cluster: staging
+units: 1
+memory: 256m
+env:
+  LOG_LEVEL: debug
+  DB_HOST: db.staging
+logging:
+  driver: awslogs
+  options:
+    awslogs-region: us-west-2
+containers:
+  - name: proxy
+    image: nginx
+    memory: 64m
+environments:
+  production:
+    cluster: production
+    units: 4
+    env:
+      DB_HOST: db.production
+      CACHE: "on"
+    logging:
+      driver: json-file
+  staging:
+    load_balancer:
+      enabled: true
+  broken:
+    port: ${COLDBREW_TEST_MISSING}
+`
+
+func TestLoad_Environment(t *testing.T) {
+	// base configuration
+	conf, err := Load([]byte(testEnvironmentsConfigYAML), flags.GlobalFlagsConfigFileFormatYAML, "app1", "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "staging", conv.S(conf.ClusterName))
+	assert.Equal(t, uint16(1), conv.U16(conf.Units))
+	assert.Equal(t, []string{"broken", "production", "staging"}, conf.EnvironmentNames())
+
+	// production overlay
+	conf, err = Load([]byte(testEnvironmentsConfigYAML), flags.GlobalFlagsConfigFileFormatYAML, "app1", "production", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "echo", conv.S(conf.Name))
+	assert.Equal(t, "production", conv.S(conf.ClusterName))
+	assert.Equal(t, uint16(4), conv.U16(conf.Units))
+	assert.Equal(t, "256m", conv.S(conf.Memory))
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "DB_HOST": "db.production", "CACHE": "on"}, conf.Env)
+	assert.Equal(t, "json-file", conv.S(conf.Logging.Driver))
+	assert.Empty(t, conf.Logging.Options)
+	assert.Len(t, conf.Containers, 1)
+	assert.Equal(t, "proxy", conv.S(conf.Containers[0].Name))
+	assert.False(t, conv.B(conf.LoadBalancer.Enabled))
+
+	// staging overlay
+	conf, err = Load([]byte(testEnvironmentsConfigYAML), flags.GlobalFlagsConfigFileFormatYAML, "app1", "staging", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "staging", conv.S(conf.ClusterName))
+	assert.True(t, conv.B(conf.LoadBalancer.Enabled))
+	assert.Equal(t, "awslogs", conv.S(conf.Logging.Driver))
+	assert.Equal(t, "us-west-2", conf.Logging.Options["awslogs-region"])
+	assert.Equal(t, "db.staging", conf.Env["DB_HOST"])
+
+	// variables of the selected environment only must be set
+	_, err = Load([]byte(testEnvironmentsConfigYAML), flags.GlobalFlagsConfigFileFormatYAML, "app1", "broken", "")
+	assert.EqualError(t, err, "Variable [COLDBREW_TEST_MISSING] referenced in [environments.broken.port] is not set.")
+
+	// unknown environment
+	_, err = Load([]byte(testEnvironmentsConfigYAML), flags.GlobalFlagsConfigFileFormatYAML, "app1", "unknown", "")
+	assert.NotNil(t, err)
+
+	// nested environments
+	_, err = Load([]byte("environments:\n  a:\n    environments:\n      b:\n        units: 1"), flags.GlobalFlagsConfigFileFormatYAML, "app1", "a", "")
+	assert.NotNil(t, err)
+
+	// overlay values are validated too
+	_, err = Load([]byte("environments:\n  a:\n    memory: lots"), flags.GlobalFlagsConfigFileFormatYAML, "app1", "a", "")
+	assert.NotNil(t, err)
+}
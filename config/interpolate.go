@@ -0,0 +1,275 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/coldbrewcloud/coldbrew-cli/flags"
+	"gopkg.in/yaml.v2"
+)
+
+// "${VAR}", "${VAR:-default}", or "$${" (escaped "${")
+var variableRE = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// variableLookupFn returns the value of the variable and whether it's set.
+type variableLookupFn func(name string) (string, bool)
+
+// interpolate expands "${VAR}" and "${VAR:-default}" in all string values of the configuration data.
+// Variables are looked up with lookupEnv (process environment) first, then in the files listed
+// in "env_file" (later files take precedence; relative paths are resolved against configDir). Values that
+// are expanded into numeric or boolean configuration fields are converted accordingly. Environments other
+// than the selected one are emptied so that their variables do not need to be set.
+func interpolate(data []byte, configFormat string, environment string, configDir string, lookupEnv variableLookupFn) ([]byte, error) {
+	if !bytes.Contains(data, []byte("${")) {
+		return data, nil
+	}
+
+	var tree interface{}
+	switch configFormat {
+	case flags.GlobalFlagsConfigFileFormatYAML:
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			return nil, fmt.Errorf("Failed to parse YAML: %s", err.Error())
+		}
+	case flags.GlobalFlagsConfigFileFormatJSON:
+		if err := json.Unmarshal(data, &tree); err != nil {
+			return nil, fmt.Errorf("Failed to parse JSON: %s", err.Error())
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported configuration format [%s]", configFormat)
+	}
+	if tree == nil {
+		return data, nil
+	}
+
+	// unselected environments
+	switch environments := mapValue(tree, "environments").(type) {
+	case map[interface{}]interface{}:
+		for k := range environments {
+			if fmt.Sprintf("%v", k) != environment {
+				environments[k] = map[interface{}]interface{}{}
+			}
+		}
+	case map[string]interface{}:
+		for k := range environments {
+			if k != environment {
+				environments[k] = map[string]interface{}{}
+			}
+		}
+	}
+
+	// env files (file paths can refer to process environment variables only)
+	root := reflect.TypeOf(Config{})
+	envFileField, _ := root.FieldByName("EnvFile")
+	envFiles, err := interpolateNode(mapValue(tree, "env_file"), envFileField.Type, "env_file", lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	fileVars := make(map[string]string)
+	if list, ok := envFiles.([]interface{}); ok {
+		for _, f := range list {
+			path, _ := f.(string)
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(configDir, path)
+			}
+			if err := readEnvFile(path, fileVars); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	lookup := func(name string) (string, bool) {
+		if v, ok := lookupEnv(name); ok {
+			return v, true
+		}
+		v, ok := fileVars[name]
+		return v, ok
+	}
+
+	tree, err = interpolateNode(tree, root, "", lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	if configFormat == flags.GlobalFlagsConfigFileFormatJSON {
+		return json.Marshal(tree)
+	}
+	return yaml.Marshal(tree)
+}
+
+func interpolateNode(node interface{}, t reflect.Type, path string, lookup variableLookupFn) (interface{}, error) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range n {
+			key := fmt.Sprintf("%v", k)
+			nv, err := interpolateNode(v, childType(t, key), joinPath(path, key), lookup)
+			if err != nil {
+				return nil, err
+			}
+			n[k] = nv
+		}
+	case map[string]interface{}:
+		for k, v := range n {
+			nv, err := interpolateNode(v, childType(t, k), joinPath(path, k), lookup)
+			if err != nil {
+				return nil, err
+			}
+			n[k] = nv
+		}
+	case []interface{}:
+		var elemType reflect.Type
+		if t != nil && t.Kind() == reflect.Slice {
+			elemType = t.Elem()
+		}
+		for i, v := range n {
+			nv, err := interpolateNode(v, elemType, fmt.Sprintf("%s[%d]", path, i), lookup)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = nv
+		}
+	case string:
+		if !strings.Contains(n, "${") {
+			return n, nil
+		}
+		expanded, err := expandVariables(n, path, lookup)
+		if err != nil {
+			return nil, err
+		}
+		return convertScalar(expanded, t, path)
+	}
+
+	return node, nil
+}
+
+// childType returns the type of the struct field (matched by its yaml tag name) or map value.
+func childType(t reflect.Type, key string) reflect.Type {
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem()
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if strings.Split(f.Tag.Get("yaml"), ",")[0] == key {
+				return f.Type
+			}
+		}
+	}
+
+	return nil
+}
+
+func expandVariables(s, path string, lookup variableLookupFn) (string, error) {
+	var err error
+	expanded := variableRE.ReplaceAllStringFunc(s, func(m string) string {
+		if m == "$${" {
+			return "${"
+		}
+
+		sm := variableRE.FindStringSubmatch(m)
+		name, hasDefault := sm[1], strings.Contains(m, ":-")
+		if v, ok := lookup(name); ok && (v != "" || !hasDefault) {
+			return v
+		}
+		if hasDefault {
+			return sm[2]
+		}
+		if err == nil {
+			err = fmt.Errorf("Variable [%s] referenced in [%s] is not set.", name, path)
+		}
+		return ""
+	})
+
+	return expanded, err
+}
+
+// convertScalar converts the expanded string into the type of the target configuration field.
+func convertScalar(s string, t reflect.Type, path string) (interface{}, error) {
+	if t == nil {
+		return s, nil
+	}
+
+	var err error
+	var v interface{}
+	switch t.Kind() {
+	case reflect.Bool:
+		v, err = strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err = strconv.ParseInt(s, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err = strconv.ParseUint(s, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		v, err = strconv.ParseFloat(s, 64)
+	default:
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid value [%s] for [%s]", s, path)
+	}
+
+	return v, nil
+}
+
+func mapValue(tree interface{}, key string) interface{} {
+	switch m := tree.(type) {
+	case map[interface{}]interface{}:
+		return m[key]
+	case map[string]interface{}:
+		return m[key]
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// readEnvFile reads "KEY=VALUE" lines from the file. Blank lines and lines starting with "#" are ignored.
+func readEnvFile(path string, vars map[string]string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read env file [%s]: %s", path, err.Error())
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		tokens := strings.SplitN(line, "=", 2)
+		if len(tokens) != 2 || strings.TrimSpace(tokens[0]) == "" {
+			return fmt.Errorf("Invalid env file [%s] at line %d", path, lineNumber)
+		}
+
+		value := strings.TrimSpace(tokens[1])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[strings.TrimSpace(tokens[0])] = value
+	}
+
+	return scanner.Err()
+}
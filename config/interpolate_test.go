@@ -0,0 +1,172 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coldbrewcloud/coldbrew-cli/flags"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"github.com/stretchr/testify/assert"
+)
+
+func testLookupEnv(vars map[string]string) variableLookupFn {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	lookup := testLookupEnv(map[string]string{
+		"APP":   "echo",
+		"PORT":  "8080",
+		"EMPTY": "",
+		"LB":    "true",
+	})
+
+	load := func(data, format string) (*Config, error) {
+		interpolated, err := interpolate([]byte(data), format, "", "", lookup)
+		if err != nil {
+			return nil, err
+		}
+		conf := &Config{}
+		if format == flags.GlobalFlagsConfigFileFormatJSON {
+			err = conf.FromJSON(interpolated)
+		} else {
+			err = conf.FromYAML(interpolated)
+		}
+		return conf, err
+	}
+
+	// no variables
+	data, err := interpolate([]byte("name: app1"), flags.GlobalFlagsConfigFileFormatYAML, "", "", lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, "name: app1", string(data))
+
+	// strings, numbers, and booleans
+	conf, err := load(`
+name: ${APP}-app
+port: ${PORT}
+load_balancer:
+  enabled: ${LB}
+  health_check:
+    path: /${MISSING:-ping}
+env:
+  DB: ${EMPTY:-localhost}
+  EMPTY: "${EMPTY}"
+  LITERAL: $${APP}
+containers:
+  - name: proxy
+    image: nginx:${TAG:-latest}
+`, flags.GlobalFlagsConfigFileFormatYAML)
+	assert.Nil(t, err)
+	assert.Equal(t, "echo-app", conv.S(conf.Name))
+	assert.Equal(t, uint16(8080), conv.U16(conf.Port))
+	assert.True(t, conv.B(conf.LoadBalancer.Enabled))
+	assert.Equal(t, "/ping", conv.S(conf.LoadBalancer.HealthCheck.Path))
+	assert.Equal(t, "localhost", conf.Env["DB"])
+	assert.Equal(t, "", conf.Env["EMPTY"])
+	assert.Equal(t, "${APP}", conf.Env["LITERAL"])
+	assert.Equal(t, "nginx:latest", conv.S(conf.Containers[0].Image))
+
+	// JSON
+	conf, err = load(`{"name":"${APP}","units":"${UNITS:-3}"}`, flags.GlobalFlagsConfigFileFormatJSON)
+	assert.Nil(t, err)
+	assert.Equal(t, "echo", conv.S(conf.Name))
+	assert.Equal(t, uint16(3), conv.U16(conf.Units))
+
+	// missing variables
+	_, err = load("load_balancer:\n  health_check:\n    path: ${MISSING}", flags.GlobalFlagsConfigFileFormatYAML)
+	assert.EqualError(t, err, "Variable [MISSING] referenced in [load_balancer.health_check.path] is not set.")
+	_, err = load("containers:\n  - name: proxy\n    image: ${MISSING}", flags.GlobalFlagsConfigFileFormatYAML)
+	assert.EqualError(t, err, "Variable [MISSING] referenced in [containers[0].image] is not set.")
+	_, err = load(`{"env":{"KEY":"${MISSING}"}}`, flags.GlobalFlagsConfigFileFormatJSON)
+	assert.EqualError(t, err, "Variable [MISSING] referenced in [env.KEY] is not set.")
+
+	// invalid typed values
+	_, err = load("port: ${APP}", flags.GlobalFlagsConfigFileFormatYAML)
+	assert.EqualError(t, err, "Invalid value [echo] for [port]")
+}
+
+func TestInterpolateEnvFile(t *testing.T) {
+	f1, err := ioutil.TempFile("", "coldbrew-env")
+	assert.Nil(t, err)
+	defer os.Remove(f1.Name())
+	f1.WriteString("# comment\n\nexport APP=file-app\nPORT=\"9000\"\nTAG=v1\n")
+	f1.Close()
+
+	f2, err := ioutil.TempFile("", "coldbrew-env")
+	assert.Nil(t, err)
+	defer os.Remove(f2.Name())
+	f2.WriteString("TAG='v2'\n")
+	f2.Close()
+
+	lookup := testLookupEnv(map[string]string{
+		"APP":     "env-app",
+		"ENV_DIR": os.TempDir(),
+	})
+
+	// later env files override earlier ones; process environment overrides env files
+	data := "name: ${APP}\nport: ${PORT}\ndocker:\n  image_tag: ${TAG}\nenv_file:\n  - " + f1.Name() + "\n  - " + f2.Name()
+	interpolated, err := interpolate([]byte(data), flags.GlobalFlagsConfigFileFormatYAML, "", "", lookup)
+	assert.Nil(t, err)
+	conf := &Config{}
+	assert.Nil(t, conf.FromYAML(interpolated))
+	assert.Equal(t, "env-app", conv.S(conf.Name))
+	assert.Equal(t, uint16(9000), conv.U16(conf.Port))
+	assert.Equal(t, "v2", conv.S(conf.Docker.ImageTag))
+
+	// missing env file
+	_, err = interpolate([]byte("name: ${APP}\nenv_file:\n  - ${ENV_DIR}/coldbrew-env-missing"), flags.GlobalFlagsConfigFileFormatYAML, "", "", lookup)
+	assert.NotNil(t, err)
+
+	// invalid env file
+	f3, err := ioutil.TempFile("", "coldbrew-env")
+	assert.Nil(t, err)
+	defer os.Remove(f3.Name())
+	f3.WriteString("INVALID\n")
+	f3.Close()
+	_, err = interpolate([]byte("name: ${APP}\nenv_file:\n  - "+f3.Name()), flags.GlobalFlagsConfigFileFormatYAML, "", "", lookup)
+	assert.EqualError(t, err, "Invalid env file ["+f3.Name()+"] at line 1")
+}
+
+func TestInterpolateRelativeEnvFile(t *testing.T) {
+	configDir, err := ioutil.TempDir("", "coldbrew-config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(configDir)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(configDir, "app.env"), []byte("APP=file-app\n"), 0644))
+
+	// relative paths are resolved against the configuration file directory, not the working directory
+	data := "name: ${APP}\nenv_file:\n  - app.env"
+	interpolated, err := interpolate([]byte(data), flags.GlobalFlagsConfigFileFormatYAML, "", configDir, testLookupEnv(nil))
+	assert.Nil(t, err)
+	conf := &Config{}
+	assert.Nil(t, conf.FromYAML(interpolated))
+	assert.Equal(t, "file-app", conv.S(conf.Name))
+
+	conf, err = Load([]byte(data), flags.GlobalFlagsConfigFileFormatYAML, "", "", configDir)
+	assert.Nil(t, err)
+	assert.Equal(t, "file-app", conv.S(conf.Name))
+
+	_, err = Load([]byte(data), flags.GlobalFlagsConfigFileFormatYAML, "", "", "")
+	assert.NotNil(t, err)
+}
+
+func TestLoadInterpolation(t *testing.T) {
+	os.Setenv("COLDBREW_TEST_APP_NAME", "app1")
+	defer os.Unsetenv("COLDBREW_TEST_APP_NAME")
+
+	conf, err := Load([]byte("name: ${COLDBREW_TEST_APP_NAME}"), flags.GlobalFlagsConfigFileFormatYAML, "", "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "app1", conv.S(conf.Name))
+	assert.Equal(t, "app1-elb", conv.S(conf.AWS.ELBLoadBalancerName))
+
+	// validation sees the expanded values
+	_, err = Load([]byte("name: ${COLDBREW_TEST_APP_NAME}_invalid!"), flags.GlobalFlagsConfigFileFormatYAML, "", "", "")
+	assert.NotNil(t, err)
+
+	_, err = Load([]byte("name: ${COLDBREW_TEST_MISSING}"), flags.GlobalFlagsConfigFileFormatYAML, "app1", "", "")
+	assert.EqualError(t, err, "Variable [COLDBREW_TEST_MISSING] referenced in [name] is not set.")
+}
@@ -2,16 +2,27 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/coldbrewcloud/coldbrew-cli/aws"
 	"github.com/coldbrewcloud/coldbrew-cli/core"
 	"github.com/coldbrewcloud/coldbrew-cli/flags"
 	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
 )
 
-func Load(data []byte, configFormat string, defaultAppName string) (*Config, error) {
+// Load parses the configuration data, overlays the named environment (if not empty), and applies defaults.
+// Relative "env_file" paths are resolved against configDir, the directory of the configuration file.
+func Load(data []byte, configFormat string, defaultAppName string, environment string, configDir string) (*Config, error) {
 	conf := &Config{}
 	configFormat = strings.ToLower(configFormat)
+
+	// expand variables before defaults and validation so that they see the final values
+	data, err := interpolate(data, configFormat, environment, configDir, os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
 	switch configFormat {
 	case flags.GlobalFlagsConfigFileFormatYAML:
 		if err := conf.FromYAML(data); err != nil {
@@ -25,6 +36,13 @@ func Load(data []byte, configFormat string, defaultAppName string) (*Config, err
 		return nil, fmt.Errorf("Unsupported configuration format [%s]", configFormat)
 	}
 
+	// environment overlay
+	if environment != "" {
+		if err := conf.ApplyEnvironment(environment); err != nil {
+			return nil, err
+		}
+	}
+
 	// env
 	if conf.Env == nil {
 		conf.Env = make(map[string]string)
@@ -52,9 +70,23 @@ func (c *Config) Defaults(source *Config) {
 
 	defS(&c.Name, source.Name)
 	defS(&c.ClusterName, source.ClusterName)
-	defU16(&c.Port, source.Port)
+	// port is a shorthand of ports: only one of them is copied from source
+	if c.Port == nil && c.Ports == nil {
+		c.Ports = source.Ports
+	}
+	if c.Ports == nil {
+		defU16(&c.Port, source.Port)
+	}
+	for _, port := range c.Ports {
+		if port != nil {
+			defU16(&port.HostPort, conv.U16P(0))
+			defS(&port.Protocol, conv.SP("tcp"))
+			defB(&port.LoadBalanced, conv.BP(false))
+		}
+	}
 	defF64(&c.CPU, source.CPU)
 	defS(&c.Memory, source.Memory)
+	defS(&c.MemoryReservation, source.MemoryReservation)
 	defU16(&c.Units, source.Units)
 
 	// envs
@@ -65,6 +97,15 @@ func (c *Config) Defaults(source *Config) {
 		c.Env[ek] = ev
 	}
 
+	// container health check
+	if c.HealthCheck.Command == nil {
+		c.HealthCheck.Command = source.HealthCheck.Command
+	}
+	c.HealthCheck.defaults(&source.HealthCheck)
+
+	// runtime options
+	c.Runtime.defaults(&source.Runtime)
+
 	// load balancer
 	defB(&c.LoadBalancer.Enabled, source.LoadBalancer.Enabled)
 	defU16(&c.LoadBalancer.Port, source.LoadBalancer.Port)
@@ -75,6 +116,67 @@ func (c *Config) Defaults(source *Config) {
 	defS(&c.LoadBalancer.HealthCheck.Timeout, source.LoadBalancer.HealthCheck.Timeout)
 	defU16(&c.LoadBalancer.HealthCheck.HealthyLimit, source.LoadBalancer.HealthCheck.HealthyLimit)
 	defU16(&c.LoadBalancer.HealthCheck.UnhealthyLimit, source.LoadBalancer.HealthCheck.UnhealthyLimit)
+	defB(&c.LoadBalancer.BlueGreen.Enabled, source.LoadBalancer.BlueGreen.Enabled)
+	defS(&c.LoadBalancer.BlueGreen.BakeTime, source.LoadBalancer.BlueGreen.BakeTime)
+
+	// deployment
+	defU16(&c.Deployment.MaxPercent, source.Deployment.MaxPercent)
+	defU16(&c.Deployment.MinHealthyPercent, source.Deployment.MinHealthyPercent)
+	defB(&c.Deployment.CircuitBreaker.Enabled, source.Deployment.CircuitBreaker.Enabled)
+	defB(&c.Deployment.CircuitBreaker.Rollback, source.Deployment.CircuitBreaker.Rollback)
+
+	// auto scaling
+	defB(&c.AutoScaling.Enabled, source.AutoScaling.Enabled)
+	defU16(&c.AutoScaling.MinUnits, source.AutoScaling.MinUnits)
+	defU16(&c.AutoScaling.MaxUnits, source.AutoScaling.MaxUnits)
+	defU16(&c.AutoScaling.CPUTarget, source.AutoScaling.CPUTarget)
+	defU16(&c.AutoScaling.MemoryTarget, source.AutoScaling.MemoryTarget)
+	defU16(&c.AutoScaling.RequestsTarget, source.AutoScaling.RequestsTarget)
+
+	// placement
+	if c.Placement.Strategy == nil {
+		c.Placement.Strategy = source.Placement.Strategy
+	}
+	if c.Placement.Constraints == nil {
+		c.Placement.Constraints = source.Placement.Constraints
+	}
+
+	// volumes and mounts
+	if c.Volumes == nil {
+		c.Volumes = source.Volumes
+	}
+	for _, volume := range c.Volumes {
+		if volume != nil {
+			volume.defaults()
+		}
+	}
+	if c.Mounts == nil {
+		c.Mounts = source.Mounts
+	}
+	defaultMounts(c.Mounts)
+
+	// IAM
+	if c.IAM.ManagedPolicies == nil {
+		c.IAM.ManagedPolicies = source.IAM.ManagedPolicies
+	}
+	if c.IAM.Statements == nil {
+		c.IAM.Statements = source.IAM.Statements
+	}
+	for _, statement := range c.IAM.Statements {
+		if statement != nil {
+			defS(&statement.Effect, conv.SP("Allow"))
+		}
+	}
+
+	// schedules
+	if c.Schedules == nil {
+		c.Schedules = source.Schedules
+	}
+	for _, schedule := range c.Schedules {
+		if schedule != nil {
+			defU16(&schedule.Count, conv.U16P(1))
+		}
+	}
 
 	// logging
 	if conv.S(c.Logging.Driver) == "" {
@@ -91,12 +193,93 @@ func (c *Config) Defaults(source *Config) {
 	// AWS
 	defS(&c.AWS.ELBLoadBalancerName, source.AWS.ELBLoadBalancerName)
 	defS(&c.AWS.ELBTargetGroupName, source.AWS.ELBTargetGroupName)
+	defS(&c.AWS.ELBGreenTargetGroupName, source.AWS.ELBGreenTargetGroupName)
 	defS(&c.AWS.ELBSecurityGroupName, source.AWS.ELBSecurityGroupName)
 	defS(&c.AWS.ELBCertificateARN, source.AWS.ELBCertificateARN)
 	defS(&c.AWS.ECRRepositoryName, source.AWS.ECRRepositoryName)
 
 	// docker
 	defS(&c.Docker.Bin, source.Docker.Bin)
+	defS(&c.Docker.ImageTag, source.Docker.ImageTag)
+
+	// containers
+	for _, container := range c.Containers {
+		if container == nil {
+			continue
+		}
+		defF64(&container.CPU, conv.F64P(0))
+		defB(&container.Essential, conv.BP(true))
+		defaultContainerHealthCheck := defaultHealthCheck()
+		container.HealthCheck.defaults(&defaultContainerHealthCheck)
+		if container.Env == nil {
+			container.Env = make(map[string]string)
+		}
+		for _, port := range container.Ports {
+			if port != nil {
+				defU16(&port.HostPort, conv.U16P(0))
+				defS(&port.Protocol, conv.SP("tcp"))
+			}
+		}
+		for _, dependency := range container.DependsOn {
+			if dependency != nil {
+				defS(&dependency.Condition, conv.SP(aws.ECSContainerDependencyConditionStart))
+			}
+		}
+		defaultMounts(container.Mounts)
+	}
+}
+
+// defaults copies timing values only: command is never inherited by other containers.
+func (h *ConfigHealthCheck) defaults(source *ConfigHealthCheck) {
+	defS(&h.Interval, source.Interval)
+	defS(&h.Timeout, source.Timeout)
+	defU16(&h.Retries, source.Retries)
+	defS(&h.StartPeriod, source.StartPeriod)
+}
+
+func (v *ConfigVolume) defaults() {
+	defS(&v.Type, conv.SP(aws.ECSVolumeTypeHost))
+	switch conv.S(v.Type) {
+	case aws.ECSVolumeTypeDocker:
+		defS(&v.Docker.Scope, conv.SP(aws.ECSDockerVolumeScopeTask))
+		defB(&v.Docker.Autoprovision, conv.BP(false))
+	case aws.ECSVolumeTypeEFS:
+		defB(&v.EFS.TransitEncryption, conv.BP(false))
+		defB(&v.EFS.IAM, conv.BP(false))
+	}
+}
+
+func defaultMounts(mounts []*ConfigMount) {
+	for _, mount := range mounts {
+		if mount != nil {
+			defB(&mount.ReadOnly, conv.BP(false))
+		}
+	}
+}
+
+// defaults copies the options that are not set: maps and lists are copied as a whole.
+func (r *ConfigRuntime) defaults(source *ConfigRuntime) {
+	if r.Command == nil {
+		r.Command = source.Command
+	}
+	if r.EntryPoint == nil {
+		r.EntryPoint = source.EntryPoint
+	}
+	defS(&r.WorkingDirectory, source.WorkingDirectory)
+	defS(&r.User, source.User)
+	if r.Ulimits == nil {
+		r.Ulimits = source.Ulimits
+	}
+	if r.Labels == nil {
+		r.Labels = source.Labels
+	}
+	if r.ExtraHosts == nil {
+		r.ExtraHosts = source.ExtraHosts
+	}
+	if r.DNSServers == nil {
+		r.DNSServers = source.DNSServers
+	}
+	defS(&r.StopTimeout, source.StopTimeout)
 }
 
 func defS(src **string, dest *string) {
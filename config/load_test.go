@@ -17,47 +17,47 @@ type testObject struct {
 
 func TestLoad(t *testing.T) {
 	// loading empty data
-	conf, err := Load([]byte(""), flags.GlobalFlagsConfigFileFormatYAML, "app1")
+	conf, err := Load([]byte(""), flags.GlobalFlagsConfigFileFormatYAML, "app1", "", "")
 	assert.Nil(t, err)
 	assert.NotNil(t, conf)
 	assert.Equal(t, "app1", conv.S(conf.Name))
-	conf, err = Load([]byte("{}"), flags.GlobalFlagsConfigFileFormatJSON, "app1")
+	conf, err = Load([]byte("{}"), flags.GlobalFlagsConfigFileFormatJSON, "app1", "", "")
 	assert.Nil(t, err)
 	assert.NotNil(t, conf)
 	assert.Equal(t, "app1", conv.S(conf.Name))
 
 	// empty data and empty app name
-	conf, err = Load([]byte(""), flags.GlobalFlagsConfigFileFormatYAML, "")
+	conf, err = Load([]byte(""), flags.GlobalFlagsConfigFileFormatYAML, "", "", "")
 	assert.NotNil(t, err)
-	conf, err = Load([]byte(""), flags.GlobalFlagsConfigFileFormatJSON, "")
+	conf, err = Load([]byte(""), flags.GlobalFlagsConfigFileFormatJSON, "", "", "")
 	assert.NotNil(t, err)
 
 	// loading "name" only data
-	conf, err = Load([]byte("name: app2"), flags.GlobalFlagsConfigFileFormatYAML, "app3")
+	conf, err = Load([]byte("name: app2"), flags.GlobalFlagsConfigFileFormatYAML, "app3", "", "")
 	assert.Nil(t, err)
 	assert.NotNil(t, conf)
 	assert.Equal(t, "app2", conv.S(conf.Name))
-	conf, err = Load([]byte("{\"name\":\"app2\"}"), flags.GlobalFlagsConfigFileFormatJSON, "app3")
+	conf, err = Load([]byte("{\"name\":\"app2\"}"), flags.GlobalFlagsConfigFileFormatJSON, "app3", "", "")
 	assert.Nil(t, err)
 	assert.NotNil(t, conf)
 	assert.Equal(t, "app2", conv.S(conf.Name))
 
 	// reference config data (YAML)
-	conf, err = Load([]byte(refConfigYAML), flags.GlobalFlagsConfigFileFormatYAML, "app4")
+	conf, err = Load([]byte(refConfigYAML), flags.GlobalFlagsConfigFileFormatYAML, "app4", "", "")
 	assert.Nil(t, err)
 	assert.NotNil(t, conf)
 	assert.Equal(t, conv.S(refConfig.Name), conv.S(conf.Name))
 	assert.Equal(t, refConfig, conf)
 
 	// reference config data (JSON)
-	conf, err = Load([]byte(refConfigJSON), flags.GlobalFlagsConfigFileFormatJSON, "app5")
+	conf, err = Load([]byte(refConfigJSON), flags.GlobalFlagsConfigFileFormatJSON, "app5", "", "")
 	assert.Nil(t, err)
 	assert.NotNil(t, conf)
 	assert.Equal(t, conv.S(refConfig.Name), conv.S(conf.Name))
 	assert.Equal(t, refConfig, conf)
 
 	// partial config data (YAML)
-	conf, err = Load([]byte(partialConfigYAML), flags.GlobalFlagsConfigFileFormatYAML, "app6")
+	conf, err = Load([]byte(partialConfigYAML), flags.GlobalFlagsConfigFileFormatYAML, "app6", "", "")
 	assert.Nil(t, err)
 	assert.NotNil(t, conf)
 	assert.Equal(t, partialConfig.Name, conf.Name)
@@ -70,6 +70,62 @@ func TestLoad(t *testing.T) {
 	assert.Equal(t, defConf.Units, conf.Units)
 	assert.Equal(t, defConf.AWS, conf.AWS)
 	assert.Equal(t, defConf.Docker, conf.Docker)
+
+	// container defaults
+	conf, err = Load([]byte(`
+containers:
+  - name: proxy
+    image: nginx
+    memory: 128m
+    ports:
+      - container_port: 80
+    depends_on:
+      - container: app7
+`), flags.GlobalFlagsConfigFileFormatYAML, "app7", "", "")
+	assert.Nil(t, err)
+	assert.Len(t, conf.Containers, 1)
+	assert.Equal(t, 0.0, conv.F64(conf.Containers[0].CPU))
+	assert.True(t, conv.B(conf.Containers[0].Essential))
+	assert.NotNil(t, conf.Containers[0].Env)
+	assert.Equal(t, uint16(0), conv.U16(conf.Containers[0].Ports[0].HostPort))
+	assert.Equal(t, "tcp", conv.S(conf.Containers[0].Ports[0].Protocol))
+	assert.Equal(t, "START", conv.S(conf.Containers[0].DependsOn[0].Condition))
+	assert.Empty(t, conf.Containers[0].HealthCheck.Command)
+	assert.Equal(t, "30s", conv.S(conf.Containers[0].HealthCheck.Interval))
+	assert.Equal(t, uint16(3), conv.U16(conf.Containers[0].HealthCheck.Retries))
+
+	// volume and mount defaults
+	conf, err = Load([]byte(`
+volumes:
+  - name: scratch
+  - name: cache
+    type: docker
+  - name: data
+    type: efs
+    efs:
+      file_system_id: fs-12345678
+mounts:
+  - volume: scratch
+    path: /tmp/scratch
+`), flags.GlobalFlagsConfigFileFormatYAML, "app8", "", "")
+	assert.Nil(t, err)
+	assert.Len(t, conf.Volumes, 3)
+	assert.Equal(t, "host", conv.S(conf.Volumes[0].Type))
+	assert.Equal(t, "task", conv.S(conf.Volumes[1].Docker.Scope))
+	assert.False(t, conv.B(conf.Volumes[1].Docker.Autoprovision))
+	assert.False(t, conv.B(conf.Volumes[2].EFS.TransitEncryption))
+	assert.False(t, conv.B(conf.Mounts[0].ReadOnly))
+
+	// schedule defaults
+	conf, err = Load([]byte(`
+schedules:
+  - name: nightly
+    expression: cron(0 3 * * ? *)
+`), flags.GlobalFlagsConfigFileFormatYAML, "app9", "", "")
+	assert.Nil(t, err)
+	assert.Len(t, conf.Schedules, 1)
+	assert.Equal(t, uint16(1), conv.U16(conf.Schedules[0].Count))
+	assert.Empty(t, conf.Schedules[0].Command)
 }
 
 func TestConfig_Defaults(t *testing.T) {
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+// AppPorts returns the port mappings of the app container: "ports", or a single load balanced TCP port
+// if only "port" is set. It returns nil if the app container exposes no ports.
+func (c *Config) AppPorts() []*ConfigPort {
+	if len(c.Ports) > 0 {
+		return c.Ports
+	}
+	if conv.U16(c.Port) == 0 {
+		return nil
+	}
+	return []*ConfigPort{
+		{
+			ContainerPort: conv.U16P(conv.U16(c.Port)),
+			HostPort:      conv.U16P(0),
+			Protocol:      conv.SP("tcp"),
+			LoadBalanced:  conv.BP(true),
+		},
+	}
+}
+
+// LoadBalancedPort returns the app container port that the ELB Target Group routes to, or 0 if there's none.
+func (c *Config) LoadBalancedPort() uint16 {
+	for _, port := range c.AppPorts() {
+		if port != nil && conv.B(port.LoadBalanced) {
+			return conv.U16(port.ContainerPort)
+		}
+	}
+	return 0
+}
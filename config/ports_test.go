@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/coldbrewcloud/coldbrew-cli/flags"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_AppPorts(t *testing.T) {
+	// port shorthand
+	conf, err := Load([]byte(`port: 8080`), flags.GlobalFlagsConfigFileFormatYAML, "app1", "", "")
+	assert.Nil(t, err)
+	assert.Nil(t, conf.Ports)
+	assert.Equal(t, []*ConfigPort{
+		{ContainerPort: conv.U16P(8080), HostPort: conv.U16P(0), Protocol: conv.SP("tcp"), LoadBalanced: conv.BP(true)},
+	}, conf.AppPorts())
+	assert.Equal(t, uint16(8080), conf.LoadBalancedPort())
+
+	// no ports
+	conf, err = Load([]byte(`port: 0`), flags.GlobalFlagsConfigFileFormatYAML, "app1", "", "")
+	assert.Nil(t, err)
+	assert.Empty(t, conf.AppPorts())
+	assert.Equal(t, uint16(0), conf.LoadBalancedPort())
+
+	// ports: port is not defaulted
+	conf, err = Load([]byte(`
+ports:
+  - container_port: 8080
+    load_balanced: true
+  - container_port: 9000
+    host_port: 9000
+    protocol: udp
+  - container_port: 9100
+`), flags.GlobalFlagsConfigFileFormatYAML, "app1", "", "")
+	assert.Nil(t, err)
+	assert.Nil(t, conf.Port)
+	assert.Equal(t, []*ConfigPort{
+		{ContainerPort: conv.U16P(8080), HostPort: conv.U16P(0), Protocol: conv.SP("tcp"), LoadBalanced: conv.BP(true)},
+		{ContainerPort: conv.U16P(9000), HostPort: conv.U16P(9000), Protocol: conv.SP("udp"), LoadBalanced: conv.BP(false)},
+		{ContainerPort: conv.U16P(9100), HostPort: conv.U16P(0), Protocol: conv.SP("tcp"), LoadBalanced: conv.BP(false)},
+	}, conf.AppPorts())
+	assert.Equal(t, uint16(8080), conf.LoadBalancedPort())
+
+	// environment overrides ports with port
+	conf, err = Load([]byte(`
+ports:
+  - container_port: 8080
+    load_balanced: true
+environments:
+  production:
+    port: 80
+`), flags.GlobalFlagsConfigFileFormatYAML, "app1", "production", "")
+	assert.Nil(t, err)
+	assert.Nil(t, conf.Ports)
+	assert.Equal(t, uint16(80), conf.LoadBalancedPort())
+}
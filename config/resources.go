@@ -0,0 +1,61 @@
+package config
+
+import (
+	"math"
+
+	"github.com/coldbrewcloud/coldbrew-cli/core"
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+)
+
+// TaskResources returns the CPU units (1 CPU = 1024 units) and memory (in MB) that ECS reserves on a
+// container instance for a single task of the app, including all sidecar containers. Memory uses the
+// memory reservation (soft limit) of a container if set, or its memory (hard limit) otherwise.
+func (c *Config) TaskResources() (uint64, uint64, error) {
+	cpu := uint64(math.Ceil(conv.F64(c.CPU) * 1024.0))
+	memory, err := reservedMemoryInMB(c.Memory, c.MemoryReservation)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, container := range c.Containers {
+		if container == nil {
+			continue
+		}
+		containerMemory, err := reservedMemoryInMB(container.Memory, container.MemoryReservation)
+		if err != nil {
+			return 0, 0, err
+		}
+		cpu += uint64(math.Ceil(conv.F64(container.CPU) * 1024.0))
+		memory += containerMemory
+	}
+
+	return cpu, memory, nil
+}
+
+// UnitsThatFit returns the number of app tasks that can be placed on a container instance
+// with the given remaining CPU units and memory (in MB).
+func UnitsThatFit(taskCPU, taskMemory uint64, remainingCPU, remainingMemory int64) uint64 {
+	if taskMemory == 0 || remainingCPU < 0 || remainingMemory < 0 {
+		return 0
+	}
+
+	units := uint64(remainingMemory) / taskMemory
+	if taskCPU > 0 {
+		if cpuUnits := uint64(remainingCPU) / taskCPU; cpuUnits < units {
+			units = cpuUnits
+		}
+	}
+	return units
+}
+
+func reservedMemoryInMB(memory, memoryReservation *string) (uint64, error) {
+	expression := conv.S(memory)
+	if memoryReservation != nil {
+		expression = conv.S(memoryReservation)
+	}
+	sizeInBytes, err := core.ParseSizeExpression(expression)
+	if err != nil {
+		return 0, err
+	}
+	return sizeInBytes / (1000 * 1000), nil
+}
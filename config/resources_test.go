@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/coldbrewcloud/coldbrew-cli/utils/conv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_TaskResources(t *testing.T) {
+	// app only: hard limit
+	conf := &Config{CPU: conv.F64P(0.5), Memory: conv.SP("500m")}
+	cpu, memory, err := conf.TaskResources()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(512), cpu)
+	assert.Equal(t, uint64(500), memory)
+
+	// app only: soft limit
+	conf.MemoryReservation = conv.SP("200m")
+	cpu, memory, err = conf.TaskResources()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(512), cpu)
+	assert.Equal(t, uint64(200), memory)
+
+	// reference config: app (1.0, 100m reserved) + proxy (0.25, 64m reserved)
+	cpu, memory, err = refConfig.TaskResources()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1024+256), cpu)
+	assert.Equal(t, uint64(100+64), memory)
+
+	// invalid memory
+	conf = &Config{CPU: conv.F64P(0.5), Memory: conv.SP("100b")}
+	_, _, err = conf.TaskResources()
+	assert.NotNil(t, err)
+}
+
+func TestUnitsThatFit(t *testing.T) {
+	assert.Equal(t, uint64(4), UnitsThatFit(256, 200, 1024, 1000)) // CPU bound
+	assert.Equal(t, uint64(2), UnitsThatFit(256, 400, 2048, 1000)) // memory bound
+	assert.Equal(t, uint64(5), UnitsThatFit(0, 200, 0, 1000))      // no CPU reserved
+	assert.Equal(t, uint64(0), UnitsThatFit(256, 200, 128, 1000))  // not enough CPU
+	assert.Equal(t, uint64(0), UnitsThatFit(256, 0, 1024, 1000))   // no memory
+	assert.Equal(t, uint64(0), UnitsThatFit(256, 200, -1, 1000))   // negative
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
+	"strings"
 
 	"github.com/coldbrewcloud/coldbrew-cli/aws"
 	"github.com/coldbrewcloud/coldbrew-cli/core"
@@ -41,6 +43,21 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("App memory cannot exceed %dM", core.MaxAppMemoryInMB)
 		}
 	}
+	if err := validateMemoryReservation(c.MemoryReservation, c.Memory); err != nil {
+		return err
+	}
+
+	if err := c.validatePorts(); err != nil {
+		return err
+	}
+
+	if err := validateHealthCheck(&c.HealthCheck); err != nil {
+		return err
+	}
+
+	if err := validateRuntime(&c.Runtime); err != nil {
+		return err
+	}
 
 	if conv.U16(c.LoadBalancer.HTTPSPort) == 0 &&
 		conv.U16(c.LoadBalancer.Port) == 0 {
@@ -71,6 +88,36 @@ func (c *Config) Validate() error {
 		return errors.New("Health check unhealthy limit cannot be 0.")
 	}
 
+	if conv.B(c.LoadBalancer.BlueGreen.Enabled) && !conv.B(c.LoadBalancer.Enabled) {
+		return errors.New("Load balancer must be enabled for blue/green deployment.")
+	}
+
+	if !core.TimeExpressionRE.MatchString(conv.S(c.LoadBalancer.BlueGreen.BakeTime)) {
+		return fmt.Errorf("Invalid blue/green bake time [%s]", conv.S(c.LoadBalancer.BlueGreen.BakeTime))
+	}
+
+	if conv.U16(c.Deployment.MinHealthyPercent) > 100 {
+		return fmt.Errorf("Deployment min healthy percent [%d] cannot exceed 100", conv.U16(c.Deployment.MinHealthyPercent))
+	}
+	if conv.U16(c.Deployment.MaxPercent) < 100 {
+		return fmt.Errorf("Deployment max percent [%d] must be at least 100", conv.U16(c.Deployment.MaxPercent))
+	}
+	if conv.U16(c.Deployment.MaxPercent) <= conv.U16(c.Deployment.MinHealthyPercent) {
+		return fmt.Errorf("Deployment max percent [%d] must be greater than min healthy percent [%d]",
+			conv.U16(c.Deployment.MaxPercent), conv.U16(c.Deployment.MinHealthyPercent))
+	}
+	if conv.B(c.Deployment.CircuitBreaker.Rollback) && !conv.B(c.Deployment.CircuitBreaker.Enabled) {
+		return errors.New("Deployment circuit breaker must be enabled to roll back.")
+	}
+
+	if err := c.validateAutoScaling(); err != nil {
+		return err
+	}
+
+	if err := c.validatePlacement(); err != nil {
+		return err
+	}
+
 	if !core.ECRRepoNameRE.MatchString(conv.S(c.AWS.ECRRepositoryName)) {
 		return fmt.Errorf("Invalid ECR Resitory name [%s]", conv.S(c.AWS.ECRRepositoryName))
 	}
@@ -83,6 +130,13 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("Invalid ELB Target Group name [%s]", conv.S(c.AWS.ELBTargetGroupName))
 	}
 
+	if !core.ELBTargetGroupNameRE.MatchString(conv.S(c.AWS.ELBGreenTargetGroupName)) {
+		return fmt.Errorf("Invalid ELB Target Group name [%s]", conv.S(c.AWS.ELBGreenTargetGroupName))
+	}
+	if conv.S(c.AWS.ELBGreenTargetGroupName) == conv.S(c.AWS.ELBTargetGroupName) {
+		return fmt.Errorf("ELB Target Group names for blue/green deployment must be different [%s]", conv.S(c.AWS.ELBTargetGroupName))
+	}
+
 	if conv.U16(c.LoadBalancer.HTTPSPort) > 0 && utils.IsBlank(conv.S(c.AWS.ELBCertificateARN)) {
 		return errors.New("Certificate ARN required to enable HTTPS.")
 	}
@@ -109,5 +163,565 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("Invalid docker executable path [%s]", conv.S(c.Docker.Bin))
 	}
 
+	if !core.DockerImageTagTemplateRE.MatchString(conv.S(c.Docker.ImageTag)) {
+		return fmt.Errorf("Invalid docker image tag [%s]", conv.S(c.Docker.ImageTag))
+	}
+
+	if err := validateEnvSecrets(c.Env); err != nil {
+		return err
+	}
+
+	if err := c.validateVolumes(); err != nil {
+		return err
+	}
+	if err := c.validateMounts(c.Mounts); err != nil {
+		return err
+	}
+
+	if err := c.validateIAM(); err != nil {
+		return err
+	}
+
+	if err := c.validateSchedules(); err != nil {
+		return err
+	}
+
+	if err := c.validateContainers(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Config) validateIAM() error {
+	if len(c.IAM.ManagedPolicies) > core.MaxIAMManagedPolicies {
+		return fmt.Errorf("IAM managed policies cannot exceed %d", core.MaxIAMManagedPolicies)
+	}
+	for _, policyARN := range c.IAM.ManagedPolicies {
+		if !core.IAMPolicyARNRE.MatchString(policyARN) {
+			return fmt.Errorf("Invalid IAM managed policy ARN [%s]", policyARN)
+		}
+	}
+
+	for _, statement := range c.IAM.Statements {
+		if statement == nil {
+			return errors.New("IAM policy statement cannot be empty.")
+		}
+		switch conv.S(statement.Effect) {
+		case "Allow", "Deny":
+		default:
+			return fmt.Errorf("Invalid IAM policy statement effect [%s] (Allow or Deny)", conv.S(statement.Effect))
+		}
+		if len(statement.Actions) == 0 {
+			return errors.New("IAM policy statement requires at least one action.")
+		}
+		if len(statement.Resources) == 0 {
+			return errors.New("IAM policy statement requires at least one resource.")
+		}
+		for _, action := range statement.Actions {
+			if action != "*" && !strings.Contains(action, ":") {
+				return fmt.Errorf("Invalid IAM policy statement action [%s] (e.g. \"s3:GetObject\")", action)
+			}
+		}
+		for _, resource := range statement.Resources {
+			if resource != "*" && !strings.HasPrefix(resource, "arn:") {
+				return fmt.Errorf("Invalid IAM policy statement resource [%s]", resource)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validatePorts() error {
+	if conv.U16(c.Port) > 0 && len(c.Ports) > 0 {
+		return errors.New("Port and ports cannot be used together: use ports only.")
+	}
+
+	mappings := make(map[string]bool)
+	loadBalanced := 0
+	for _, port := range c.Ports {
+		if port == nil || conv.U16(port.ContainerPort) == 0 {
+			return errors.New("Container port is required for ports.")
+		}
+		protocol := conv.S(port.Protocol)
+		switch protocol {
+		case "tcp", "udp":
+		default:
+			return fmt.Errorf("Invalid port protocol [%s]", protocol)
+		}
+		mapping := fmt.Sprintf("%s:%d", protocol, conv.U16(port.ContainerPort))
+		if mappings[mapping] {
+			return fmt.Errorf("Duplicate port [%s]", mapping)
+		}
+		mappings[mapping] = true
+
+		if conv.B(port.LoadBalanced) {
+			if protocol != "tcp" {
+				return fmt.Errorf("Load balanced port [%s] must be a TCP port", mapping)
+			}
+			loadBalanced++
+		}
+	}
+	if loadBalanced > 1 {
+		return errors.New("Only one of ports can be load balanced.")
+	}
+
+	if conv.B(c.LoadBalancer.Enabled) && c.LoadBalancedPort() == 0 {
+		return errors.New("Load balancer requires app port: set port, or load_balanced in one of ports.")
+	}
+
+	return nil
+}
+
+func (c *Config) validateAutoScaling() error {
+	if !conv.B(c.AutoScaling.Enabled) {
+		return nil
+	}
+
+	minUnits, maxUnits := conv.U16(c.AutoScaling.MinUnits), conv.U16(c.AutoScaling.MaxUnits)
+	if maxUnits == 0 {
+		return errors.New("Auto scaling max units cannot be 0.")
+	}
+	if maxUnits > core.MaxAppUnits {
+		return fmt.Errorf("Auto scaling max units cannot exceed %d", core.MaxAppUnits)
+	}
+	if minUnits > maxUnits {
+		return fmt.Errorf("Auto scaling min units [%d] cannot exceed max units [%d]", minUnits, maxUnits)
+	}
+
+	cpuTarget, memoryTarget, requestsTarget := conv.U16(c.AutoScaling.CPUTarget), conv.U16(c.AutoScaling.MemoryTarget), conv.U16(c.AutoScaling.RequestsTarget)
+	if cpuTarget == 0 && memoryTarget == 0 && requestsTarget == 0 {
+		return errors.New("Auto scaling requires at least one of CPU, memory, or requests target.")
+	}
+	if cpuTarget > 100 {
+		return fmt.Errorf("Auto scaling CPU target [%d] cannot exceed 100", cpuTarget)
+	}
+	if memoryTarget > 100 {
+		return fmt.Errorf("Auto scaling memory target [%d] cannot exceed 100", memoryTarget)
+	}
+	if requestsTarget > 0 && !conv.B(c.LoadBalancer.Enabled) {
+		return errors.New("Load balancer must be enabled for auto scaling on requests target.")
+	}
+
+	return nil
+}
+
+func (c *Config) validatePlacement() error {
+	if len(c.Placement.Strategy) > core.MaxPlacementStrategies {
+		return fmt.Errorf("Placement strategies cannot exceed %d", core.MaxPlacementStrategies)
+	}
+	for _, strategy := range c.Placement.Strategy {
+		if strategy == nil {
+			return errors.New("Placement strategy cannot be empty.")
+		}
+		field := conv.S(strategy.Field)
+		switch conv.S(strategy.Type) {
+		case aws.ECSPlacementStrategyTypeSpread:
+			if utils.IsBlank(field) {
+				return errors.New("Placement strategy [spread] requires field. (e.g. \"attribute:ecs.availability-zone\", \"instanceId\")")
+			}
+		case aws.ECSPlacementStrategyTypeBinpack:
+			if field != "cpu" && field != "memory" {
+				return fmt.Errorf("Invalid field [%s] for placement strategy [binpack] (cpu or memory)", field)
+			}
+		case aws.ECSPlacementStrategyTypeRandom:
+			if field != "" {
+				return fmt.Errorf("Placement strategy [random] does not take field [%s]", field)
+			}
+		default:
+			return fmt.Errorf("Invalid placement strategy type [%s]", conv.S(strategy.Type))
+		}
+	}
+
+	if len(c.Placement.Constraints) > core.MaxPlacementConstraints {
+		return fmt.Errorf("Placement constraints cannot exceed %d", core.MaxPlacementConstraints)
+	}
+	for _, constraint := range c.Placement.Constraints {
+		if constraint == nil {
+			return errors.New("Placement constraint cannot be empty.")
+		}
+		expression := conv.S(constraint.Expression)
+		switch conv.S(constraint.Type) {
+		case aws.ECSPlacementConstraintTypeDistinctInstance:
+			if expression != "" {
+				return fmt.Errorf("Placement constraint [distinctInstance] does not take expression [%s]", expression)
+			}
+		case aws.ECSPlacementConstraintTypeMemberOf:
+			if utils.IsBlank(expression) {
+				return errors.New("Placement constraint [memberOf] requires expression.")
+			}
+		default:
+			return fmt.Errorf("Invalid placement constraint type [%s]", conv.S(constraint.Type))
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateSchedules() error {
+	scheduleNames := make(map[string]bool)
+	for _, schedule := range c.Schedules {
+		if schedule == nil {
+			return errors.New("Schedule cannot be empty.")
+		}
+		name := conv.S(schedule.Name)
+		if !core.ScheduleNameRE.MatchString(name) {
+			return fmt.Errorf("Invalid schedule name [%s]", name)
+		}
+		if ruleName := core.DefaultCloudWatchEventsRuleName(conv.S(c.ClusterName), conv.S(c.Name), name); len(ruleName) > core.MaxCloudWatchEventsRuleNameLength {
+			return fmt.Errorf("Schedule name [%s] is too long: CloudWatch Events Rule name [%s] cannot exceed %d characters", name, ruleName, core.MaxCloudWatchEventsRuleNameLength)
+		}
+		if scheduleNames[name] {
+			return fmt.Errorf("Duplicate schedule name [%s]", name)
+		}
+		scheduleNames[name] = true
+
+		if !core.ScheduleExpressionRE.MatchString(conv.S(schedule.Expression)) {
+			return fmt.Errorf("Invalid expression [%s] for schedule [%s] (e.g. \"cron(0 3 * * ? *)\" or \"rate(1 hour)\")", conv.S(schedule.Expression), name)
+		}
+		if count := conv.U16(schedule.Count); count == 0 || count > core.MaxScheduledTaskCount {
+			return fmt.Errorf("Task count for schedule [%s] must be between 1 and %d", name, core.MaxScheduledTaskCount)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateVolumes() error {
+	volumeNames := make(map[string]bool)
+	for _, volume := range c.Volumes {
+		if volume == nil {
+			return errors.New("Volume cannot be empty.")
+		}
+		name := conv.S(volume.Name)
+		if !core.VolumeNameRE.MatchString(name) {
+			return fmt.Errorf("Invalid volume name [%s]", name)
+		}
+		if volumeNames[name] {
+			return fmt.Errorf("Duplicate volume name [%s]", name)
+		}
+		volumeNames[name] = true
+
+		volumeType := conv.S(volume.Type)
+		if volumeType != aws.ECSVolumeTypeHost && conv.S(volume.HostPath) != "" {
+			return fmt.Errorf("Volume [%s] of type [%s] does not take host path", name, volumeType)
+		}
+
+		switch volumeType {
+		case aws.ECSVolumeTypeHost:
+			if hostPath := conv.S(volume.HostPath); hostPath != "" && !strings.HasPrefix(hostPath, "/") {
+				return fmt.Errorf("Host path [%s] of volume [%s] must be an absolute path", hostPath, name)
+			}
+		case aws.ECSVolumeTypeDocker:
+			switch conv.S(volume.Docker.Scope) {
+			case aws.ECSDockerVolumeScopeTask:
+				if conv.B(volume.Docker.Autoprovision) {
+					return fmt.Errorf("Docker volume [%s] can be autoprovisioned only with scope [%s]", name, aws.ECSDockerVolumeScopeShared)
+				}
+			case aws.ECSDockerVolumeScopeShared:
+			default:
+				return fmt.Errorf("Invalid scope [%s] for Docker volume [%s]", conv.S(volume.Docker.Scope), name)
+			}
+		case aws.ECSVolumeTypeEFS:
+			if !core.EFSFileSystemIDRE.MatchString(conv.S(volume.EFS.FileSystemID)) {
+				return fmt.Errorf("Invalid EFS file system ID [%s] for volume [%s]", conv.S(volume.EFS.FileSystemID), name)
+			}
+			accessPointID := conv.S(volume.EFS.AccessPointID)
+			if accessPointID != "" {
+				if !core.EFSAccessPointIDRE.MatchString(accessPointID) {
+					return fmt.Errorf("Invalid EFS access point ID [%s] for volume [%s]", accessPointID, name)
+				}
+				if rootDirectory := conv.S(volume.EFS.RootDirectory); rootDirectory != "" && rootDirectory != "/" {
+					return fmt.Errorf("EFS volume [%s] cannot have root directory [%s] with access point", name, rootDirectory)
+				}
+			}
+			if (accessPointID != "" || conv.B(volume.EFS.IAM)) && !conv.B(volume.EFS.TransitEncryption) {
+				return fmt.Errorf("EFS volume [%s] requires transit encryption to use access point or IAM authorization", name)
+			}
+		default:
+			return fmt.Errorf("Invalid volume type [%s] for volume [%s]", volumeType, name)
+		}
+	}
+
+	return nil
+}
+
+// validateMounts checks the mounts of a container: each mount must reference a volume defined in the configuration.
+func (c *Config) validateMounts(mounts []*ConfigMount) error {
+	paths := make(map[string]bool)
+	for _, mount := range mounts {
+		if mount == nil {
+			return errors.New("Mount cannot be empty.")
+		}
+
+		volumeName := conv.S(mount.Volume)
+		found := false
+		for _, volume := range c.Volumes {
+			if conv.S(volume.Name) == volumeName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("Mount references undefined volume [%s]", volumeName)
+		}
+
+		path := conv.S(mount.Path)
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("Mount path [%s] of volume [%s] must be an absolute path", path, volumeName)
+		}
+		if paths[path] {
+			return fmt.Errorf("Duplicate mount path [%s]", path)
+		}
+		paths[path] = true
+	}
+
+	return nil
+}
+
+func (c *Config) validateContainers() error {
+	containerNames := map[string]bool{conv.S(c.Name): true}
+	for _, container := range c.Containers {
+		if container == nil {
+			return errors.New("Container definition cannot be empty.")
+		}
+		name := conv.S(container.Name)
+		if !core.ContainerNameRE.MatchString(name) {
+			return fmt.Errorf("Invalid container name [%s]", name)
+		}
+		if containerNames[name] {
+			return fmt.Errorf("Duplicate container name [%s]", name)
+		}
+		containerNames[name] = true
+	}
+
+	for _, container := range c.Containers {
+		name := conv.S(container.Name)
+
+		if utils.IsBlank(conv.S(container.Image)) || !core.DockerImageURIRE.MatchString(conv.S(container.Image)) {
+			return fmt.Errorf("Invalid image [%s] for container [%s]", conv.S(container.Image), name)
+		}
+
+		if conv.F64(container.CPU) < 0 || conv.F64(container.CPU) > core.MaxAppCPU {
+			return fmt.Errorf("Invalid CPU [%.2f] for container [%s]", conv.F64(container.CPU), name)
+		}
+
+		if !core.SizeExpressionRE.MatchString(conv.S(container.Memory)) {
+			return fmt.Errorf("Invalid memory [%s] for container [%s]", conv.S(container.Memory), name)
+		}
+		sizeInBytes, err := core.ParseSizeExpression(conv.S(container.Memory))
+		if err != nil {
+			return fmt.Errorf("Invalid memory for container [%s]: %s", name, err.Error())
+		}
+		if sizeInBytes == 0 || sizeInBytes > core.MaxAppMemoryInMB*1000*1000 {
+			return fmt.Errorf("Memory for container [%s] must be between 1M and %dM", name, core.MaxAppMemoryInMB)
+		}
+		if err := validateMemoryReservation(container.MemoryReservation, container.Memory); err != nil {
+			return fmt.Errorf("%s (container [%s])", err.Error(), name)
+		}
+
+		if err := validateEnvSecrets(container.Env); err != nil {
+			return fmt.Errorf("%s (container [%s])", err.Error(), name)
+		}
+
+		if err := validateHealthCheck(&container.HealthCheck); err != nil {
+			return fmt.Errorf("%s (container [%s])", err.Error(), name)
+		}
+
+		if err := c.validateMounts(container.Mounts); err != nil {
+			return fmt.Errorf("%s (container [%s])", err.Error(), name)
+		}
+
+		if err := validateRuntime(&container.Runtime); err != nil {
+			return fmt.Errorf("%s (container [%s])", err.Error(), name)
+		}
+
+		for _, port := range container.Ports {
+			if port == nil || conv.U16(port.ContainerPort) == 0 {
+				return fmt.Errorf("Container port is required for container [%s]", name)
+			}
+			switch conv.S(port.Protocol) {
+			case "tcp", "udp":
+			default:
+				return fmt.Errorf("Invalid port protocol [%s] for container [%s]", conv.S(port.Protocol), name)
+			}
+			if conv.B(port.LoadBalanced) {
+				return fmt.Errorf("Only app container port can be load balanced (container [%s])", name)
+			}
+		}
+
+		for _, dependency := range container.DependsOn {
+			if dependency == nil {
+				return fmt.Errorf("Container dependency cannot be empty for container [%s]", name)
+			}
+			dependsOn := conv.S(dependency.Container)
+			if dependsOn == name || !containerNames[dependsOn] {
+				return fmt.Errorf("Invalid container dependency [%s] for container [%s]", dependsOn, name)
+			}
+			switch conv.S(dependency.Condition) {
+			case aws.ECSContainerDependencyConditionStart,
+				aws.ECSContainerDependencyConditionComplete,
+				aws.ECSContainerDependencyConditionSuccess:
+			case aws.ECSContainerDependencyConditionHealthy:
+				if !c.hasHealthCheck(dependsOn) {
+					return fmt.Errorf("Container [%s] needs health check for dependency condition [%s] of container [%s]",
+						dependsOn, conv.S(dependency.Condition), name)
+				}
+			default:
+				return fmt.Errorf("Invalid container dependency condition [%s] for container [%s]", conv.S(dependency.Condition), name)
+			}
+		}
+
+		for _, link := range container.Links {
+			linkName := strings.SplitN(link, ":", 2)[0]
+			if linkName == name || !containerNames[linkName] {
+				return fmt.Errorf("Invalid container link [%s] for container [%s]", link, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasHealthCheck returns true if the app container or the additional container has health check command.
+func (c *Config) hasHealthCheck(containerName string) bool {
+	if containerName == conv.S(c.Name) {
+		return len(c.HealthCheck.Command) > 0
+	}
+	for _, container := range c.Containers {
+		if conv.S(container.Name) == containerName {
+			return len(container.HealthCheck.Command) > 0
+		}
+	}
+	return false
+}
+
+// validateHealthCheck checks the health check against ECS limits. Timing values are not checked if the
+// health check is disabled.
+func validateHealthCheck(h *ConfigHealthCheck) error {
+	if len(h.Command) == 0 {
+		return nil
+	}
+	if (h.Command[0] != "CMD" && h.Command[0] != "CMD-SHELL") || len(h.Command) < 2 {
+		return fmt.Errorf("Health check command must start with \"CMD\" or \"CMD-SHELL\" followed by the command %v", h.Command)
+	}
+
+	for _, t := range []struct {
+		name     string
+		value    *string
+		min, max uint64
+	}{
+		{"interval", h.Interval, 5, 300},
+		{"timeout", h.Timeout, 2, 60},
+		{"start period", h.StartPeriod, 0, 300},
+	} {
+		seconds, err := core.ParseTimeExpression(conv.S(t.value))
+		if err != nil {
+			return fmt.Errorf("Invalid health check %s [%s]", t.name, conv.S(t.value))
+		}
+		if seconds < t.min || seconds > t.max {
+			return fmt.Errorf("Health check %s [%s] must be between %ds and %ds", t.name, conv.S(t.value), t.min, t.max)
+		}
+	}
+
+	if conv.U16(h.Retries) < 1 || conv.U16(h.Retries) > 10 {
+		return fmt.Errorf("Health check retries [%d] must be between 1 and 10", conv.U16(h.Retries))
+	}
+
+	return nil
+}
+
+// validateMemoryReservation checks that the memory reservation (soft limit), if set, does not exceed
+// the memory (hard limit). Memory itself must be validated before.
+func validateMemoryReservation(reservation, memory *string) error {
+	if reservation == nil {
+		return nil
+	}
+
+	reservationInBytes, err := core.ParseSizeExpression(conv.S(reservation))
+	if err != nil {
+		return fmt.Errorf("Invalid memory reservation [%s]", conv.S(reservation))
+	}
+	if reservationInBytes < 1000*1000 {
+		return fmt.Errorf("Memory reservation [%s] must be at least 1M", conv.S(reservation))
+	}
+	memoryInBytes, err := core.ParseSizeExpression(conv.S(memory))
+	if err != nil {
+		return fmt.Errorf("Invalid memory [%s]", conv.S(memory))
+	}
+	if reservationInBytes > memoryInBytes {
+		return fmt.Errorf("Memory reservation [%s] cannot exceed memory [%s]", conv.S(reservation), conv.S(memory))
+	}
+
+	return nil
+}
+
+func validateRuntime(r *ConfigRuntime) error {
+	ulimitNames := make(map[string]bool)
+	for _, ulimit := range r.Ulimits {
+		if ulimit == nil {
+			return errors.New("Ulimit cannot be empty.")
+		}
+		name := conv.S(ulimit.Name)
+		valid := false
+		for _, n := range core.UlimitNames {
+			if n == name {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("Invalid ulimit name [%s] (one of %s)", name, strings.Join(core.UlimitNames, ", "))
+		}
+		if ulimitNames[name] {
+			return fmt.Errorf("Duplicate ulimit [%s]", name)
+		}
+		ulimitNames[name] = true
+		if conv.U64(ulimit.Soft) > conv.U64(ulimit.Hard) {
+			return fmt.Errorf("Soft limit [%d] of ulimit [%s] cannot exceed hard limit [%d]", conv.U64(ulimit.Soft), name, conv.U64(ulimit.Hard))
+		}
+	}
+
+	for k := range r.Labels {
+		if utils.IsBlank(k) {
+			return errors.New("Docker label name cannot be empty.")
+		}
+	}
+
+	for hostname, ipAddress := range r.ExtraHosts {
+		if utils.IsBlank(hostname) {
+			return errors.New("Extra host name cannot be empty.")
+		}
+		if net.ParseIP(ipAddress) == nil {
+			return fmt.Errorf("Invalid IP address [%s] for extra host [%s]", ipAddress, hostname)
+		}
+	}
+
+	for _, dnsServer := range r.DNSServers {
+		if net.ParseIP(dnsServer) == nil {
+			return fmt.Errorf("Invalid DNS server IP address [%s]", dnsServer)
+		}
+	}
+
+	if stopTimeout := conv.S(r.StopTimeout); stopTimeout != "" {
+		seconds, err := core.ParseTimeExpression(stopTimeout)
+		if err != nil {
+			return fmt.Errorf("Invalid stop timeout [%s]", stopTimeout)
+		}
+		if seconds > core.MaxContainerStopTimeoutInSeconds {
+			return fmt.Errorf("Stop timeout [%s] cannot exceed %ds", stopTimeout, core.MaxContainerStopTimeoutInSeconds)
+		}
+	}
+
+	return nil
+}
+
+func validateEnvSecrets(envs map[string]string) error {
+	for k, v := range envs {
+		if _, reference, ok := core.ParseEnvSecret(v); ok && utils.IsBlank(reference) {
+			return fmt.Errorf("Invalid secret reference [%s] for env [%s]", v, k)
+		}
+	}
 	return nil
 }
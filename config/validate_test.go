@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/coldbrewcloud/coldbrew-cli/core"
@@ -79,6 +80,40 @@ func TestConfig_Validate(t *testing.T) {
 	err = conf.Validate()
 	assert.Nil(t, err)
 
+	// Ports
+	conf = DefaultConfig("app1")
+	conf.Port = nil
+	conf.Ports = []*ConfigPort{
+		{ContainerPort: conv.U16P(8080), HostPort: conv.U16P(0), Protocol: conv.SP("tcp"), LoadBalanced: conv.BP(true)},
+		{ContainerPort: conv.U16P(9000), HostPort: conv.U16P(9000), Protocol: conv.SP("udp"), LoadBalanced: conv.BP(false)},
+	}
+	assert.Nil(t, conf.Validate())
+	conf.Port = conv.U16P(80) // port and ports
+	assert.NotNil(t, conf.Validate())
+	conf.Port = nil
+	conf.Ports[1].Protocol = conv.SP("sctp")
+	assert.NotNil(t, conf.Validate())
+	conf.Ports[1].Protocol = conv.SP("udp")
+	conf.Ports[1].LoadBalanced = conv.BP(true) // UDP cannot be load balanced
+	assert.NotNil(t, conf.Validate())
+	conf.Ports[1].Protocol = conv.SP("tcp") // more than one load balanced
+	assert.NotNil(t, conf.Validate())
+	conf.Ports[1].LoadBalanced = conv.BP(false)
+	conf.Ports[1].ContainerPort = conv.U16P(8080) // duplicate
+	assert.NotNil(t, conf.Validate())
+	conf.Ports[1].Protocol = conv.SP("udp") // same port number with different protocol (OK)
+	assert.Nil(t, conf.Validate())
+	conf.Ports[1].ContainerPort = conv.U16P(0)
+	assert.NotNil(t, conf.Validate())
+	conf.Ports[1].ContainerPort = conv.U16P(9000)
+	conf.LoadBalancer.Enabled = conv.BP(true)
+	assert.Nil(t, conf.Validate())
+	conf.Ports[0].LoadBalanced = conv.BP(false) // load balancer without load balanced port
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].Ports[0].LoadBalanced = conv.BP(true) // additional container
+	assert.NotNil(t, conf.Validate())
+
 	// CPU
 	conf = DefaultConfig("app1")
 	conf.CPU = nil // nil
@@ -127,6 +162,24 @@ func TestConfig_Validate(t *testing.T) {
 	err = conf.Validate()
 	assert.NotNil(t, err)
 
+	// Memory Reservation
+	conf = DefaultConfig("app1")
+	conf.Memory = conv.SP("500m")
+	conf.MemoryReservation = nil // nil: no soft limit
+	assert.Nil(t, conf.Validate())
+	conf.MemoryReservation = conv.SP("250m") // < memory
+	assert.Nil(t, conf.Validate())
+	conf.MemoryReservation = conv.SP("500m") // = memory
+	assert.Nil(t, conf.Validate())
+	conf.MemoryReservation = conv.SP("1g") // > memory
+	assert.NotNil(t, conf.Validate())
+	conf.MemoryReservation = conv.SP("") // empty
+	assert.NotNil(t, conf.Validate())
+	conf.MemoryReservation = conv.SP("0") // zero
+	assert.NotNil(t, conf.Validate())
+	conf.MemoryReservation = conv.SP("100b") // invalid
+	assert.NotNil(t, conf.Validate())
+
 	// Env
 	conf = DefaultConfig("app1")
 	conf.Env = nil // nil
@@ -245,6 +298,241 @@ func TestConfig_Validate(t *testing.T) {
 	conf.LoadBalancer.HealthCheck.UnhealthyLimit = conv.U16P(0)
 	assert.NotNil(t, conf.Validate())
 
+	// Blue/Green
+	conf = DefaultConfig("app1")
+	conf.LoadBalancer.BlueGreen.Enabled = conv.BP(true)
+	assert.NotNil(t, conf.Validate()) // load balancer disabled
+	conf.LoadBalancer.Enabled = conv.BP(true)
+	assert.Nil(t, conf.Validate())
+	conf.LoadBalancer.BlueGreen.BakeTime = conv.SP("0")
+	assert.Nil(t, conf.Validate())
+	conf.LoadBalancer.BlueGreen.BakeTime = conv.SP("")
+	assert.NotNil(t, conf.Validate())
+	conf.LoadBalancer.BlueGreen.BakeTime = conv.SP("10x")
+	assert.NotNil(t, conf.Validate())
+
+	// Deployment
+	conf = DefaultConfig("app1")
+	conf.Deployment.MinHealthyPercent = conv.U16P(100)
+	assert.Nil(t, conf.Validate())
+	conf.Deployment.MinHealthyPercent = conv.U16P(101)
+	assert.NotNil(t, conf.Validate())
+	conf.Deployment.MinHealthyPercent = conv.U16P(0)
+	conf.Deployment.MaxPercent = conv.U16P(100)
+	assert.Nil(t, conf.Validate())
+	conf.Deployment.MaxPercent = conv.U16P(99)
+	assert.NotNil(t, conf.Validate())
+	conf.Deployment.MinHealthyPercent = conv.U16P(100)
+	conf.Deployment.MaxPercent = conv.U16P(100) // no room for new tasks
+	assert.NotNil(t, conf.Validate())
+	conf = DefaultConfig("app1")
+	conf.Deployment.CircuitBreaker.Rollback = conv.BP(true)
+	assert.NotNil(t, conf.Validate())
+	conf.Deployment.CircuitBreaker.Enabled = conv.BP(true)
+	assert.Nil(t, conf.Validate())
+
+	// Auto Scaling
+	conf = DefaultConfig("app1")
+	conf.AutoScaling.Enabled = conv.BP(true)
+	assert.NotNil(t, conf.Validate()) // no target
+	conf.AutoScaling.CPUTarget = conv.U16P(70)
+	assert.Nil(t, conf.Validate())
+	conf.AutoScaling.CPUTarget = conv.U16P(101)
+	assert.NotNil(t, conf.Validate())
+	conf.AutoScaling.CPUTarget = conv.U16P(0)
+	conf.AutoScaling.MemoryTarget = conv.U16P(80)
+	assert.Nil(t, conf.Validate())
+	conf.AutoScaling.MinUnits = conv.U16P(5) // min > max
+	assert.NotNil(t, conf.Validate())
+	conf.AutoScaling.MaxUnits = conv.U16P(5)
+	assert.Nil(t, conf.Validate())
+	conf.AutoScaling.MaxUnits = conv.U16P(core.MaxAppUnits + 1)
+	assert.NotNil(t, conf.Validate())
+	conf.AutoScaling.MinUnits = conv.U16P(0)
+	conf.AutoScaling.MaxUnits = conv.U16P(0)
+	assert.NotNil(t, conf.Validate())
+	conf = DefaultConfig("app1")
+	conf.AutoScaling.Enabled = conv.BP(true)
+	conf.AutoScaling.RequestsTarget = conv.U16P(1000)
+	assert.NotNil(t, conf.Validate()) // load balancer disabled
+	conf.LoadBalancer.Enabled = conv.BP(true)
+	assert.Nil(t, conf.Validate())
+
+	// Container Health Check
+	conf = DefaultConfig("app1")
+	conf.HealthCheck.Command = []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"}
+	assert.Nil(t, conf.Validate())
+	conf.HealthCheck.Command = []string{"curl -f http://localhost/ || exit 1"} // no CMD/CMD-SHELL
+	assert.NotNil(t, conf.Validate())
+	conf.HealthCheck.Command = []string{"CMD"} // no command
+	assert.NotNil(t, conf.Validate())
+	conf.HealthCheck.Command = []string{"CMD", "/healthcheck"}
+	conf.HealthCheck.Interval = conv.SP("4s") // too short
+	assert.NotNil(t, conf.Validate())
+	conf.HealthCheck.Interval = conv.SP("5m")
+	assert.Nil(t, conf.Validate())
+	conf.HealthCheck.Timeout = conv.SP("2m") // too long
+	assert.NotNil(t, conf.Validate())
+	conf.HealthCheck.Timeout = conv.SP("1m")
+	conf.HealthCheck.Retries = conv.U16P(0)
+	assert.NotNil(t, conf.Validate())
+	conf.HealthCheck.Retries = conv.U16P(10)
+	conf.HealthCheck.StartPeriod = conv.SP("10x")
+	assert.NotNil(t, conf.Validate())
+	conf.HealthCheck.Command = nil // disabled: timing values are not checked
+	assert.Nil(t, conf.Validate())
+
+	// Placement
+	conf = DefaultConfig("app1")
+	conf.Placement.Strategy = []*ConfigPlacementStrategy{{Type: conv.SP("spread"), Field: conv.SP("instanceId")}}
+	assert.Nil(t, conf.Validate())
+	conf.Placement.Strategy[0].Field = nil // spread requires field
+	assert.NotNil(t, conf.Validate())
+	conf.Placement.Strategy[0] = &ConfigPlacementStrategy{Type: conv.SP("binpack"), Field: conv.SP("memory")}
+	assert.Nil(t, conf.Validate())
+	conf.Placement.Strategy[0].Field = conv.SP("disk")
+	assert.NotNil(t, conf.Validate())
+	conf.Placement.Strategy[0] = &ConfigPlacementStrategy{Type: conv.SP("random")}
+	assert.Nil(t, conf.Validate())
+	conf.Placement.Strategy[0] = &ConfigPlacementStrategy{Type: conv.SP("pack")}
+	assert.NotNil(t, conf.Validate())
+	conf = DefaultConfig("app1")
+	conf.Placement.Constraints = []*ConfigPlacementConstraint{{Type: conv.SP("distinctInstance")}}
+	assert.Nil(t, conf.Validate())
+	conf.Placement.Constraints[0].Expression = conv.SP("attribute:ecs.instance-type == t2.micro")
+	assert.NotNil(t, conf.Validate())
+	conf.Placement.Constraints[0].Type = conv.SP("memberOf")
+	assert.Nil(t, conf.Validate())
+	conf.Placement.Constraints[0].Expression = nil // memberOf requires expression
+	assert.NotNil(t, conf.Validate())
+
+	// Volumes and Mounts
+	conf = testClone(refConfig)
+	assert.Nil(t, conf.Validate())
+	conf.Volumes[0].Name = conv.SP("bad volume") // invalid character
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Volumes[1].Name = conv.SP("scratch") // duplicate name
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Volumes[0].HostPath = conv.SP("/var/data")
+	assert.Nil(t, conf.Validate())
+	conf.Volumes[0].HostPath = conv.SP("var/data") // not absolute
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Volumes[0].Type = conv.SP("nfs")
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Volumes[1].HostPath = conv.SP("/var/data") // host path for docker volume
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Volumes[1].Docker.Scope = conv.SP("task") // autoprovision requires shared scope
+	assert.NotNil(t, conf.Validate())
+	conf.Volumes[1].Docker.Autoprovision = conv.BP(false)
+	assert.Nil(t, conf.Validate())
+	conf.Volumes[1].Docker.Scope = conv.SP("global")
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Volumes[2].EFS.FileSystemID = conv.SP("12345678")
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Volumes[2].EFS.TransitEncryption = conv.BP(false) // access point requires transit encryption
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Volumes[2].EFS.RootDirectory = conv.SP("/shared") // access point sets root directory
+	assert.NotNil(t, conf.Validate())
+	conf.Volumes[2].EFS.AccessPointID = nil
+	conf.Volumes[2].EFS.TransitEncryption = conv.BP(false)
+	assert.Nil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Mounts[0].Volume = conv.SP("unknown") // undefined volume
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Mounts[0].Path = conv.SP("tmp") // not absolute
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Mounts[1].Path = conv.SP("/tmp/scratch") // duplicate path
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].Mounts[0].Volume = conv.SP("unknown") // undefined volume
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Volumes = nil // mounts reference undefined volumes
+	assert.NotNil(t, conf.Validate())
+
+	// Runtime
+	conf = testClone(refConfig)
+	assert.Nil(t, conf.Validate())
+	conf.Runtime.Ulimits[0].Name = conv.SP("files")
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Runtime.Ulimits = append(conf.Runtime.Ulimits, testClone(refConfig).Runtime.Ulimits[0]) // duplicate
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Runtime.Ulimits[0].Soft = conv.U64P(100000) // soft > hard
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Runtime.ExtraHosts["db.internal"] = "db"
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Runtime.DNSServers = []string{"10.0.0.256"}
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Runtime.StopTimeout = conv.SP("2m")
+	assert.Nil(t, conf.Validate())
+	conf.Runtime.StopTimeout = conv.SP("121s")
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].Runtime.StopTimeout = conv.SP("1h") // additional container
+	assert.NotNil(t, conf.Validate())
+
+	// IAM
+	conf = testClone(refConfig)
+	assert.Nil(t, conf.Validate())
+	conf.IAM.ManagedPolicies = []string{"AmazonS3ReadOnlyAccess"} // not an ARN
+	assert.NotNil(t, conf.Validate())
+	conf.IAM.ManagedPolicies = []string{"arn:aws:iam::123456789012:policy/echo-policy"}
+	assert.Nil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.IAM.Statements[0].Effect = conv.SP("allow")
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.IAM.Statements[0].Actions = nil
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.IAM.Statements[0].Actions = []string{"SendMessage"} // no service prefix
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.IAM.Statements[0].Resources = nil
+	assert.NotNil(t, conf.Validate())
+	conf.IAM.Statements[0].Resources = []string{"*"}
+	assert.Nil(t, conf.Validate())
+	conf.IAM.Statements[0].Resources = []string{"echo-queue"}
+	assert.NotNil(t, conf.Validate())
+
+	// Schedules
+	conf = testClone(refConfig)
+	conf.Schedules[0].Name = conv.SP("nightly.report") // no dots
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Schedules[1].Name = conv.SP("nightly-report") // duplicate
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Schedules[0].Name = conv.SP(strings.Repeat("a", 50)) // rule name too long
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Schedules[0].Expression = conv.SP("0 3 * * ? *") // not cron(...)
+	assert.NotNil(t, conf.Validate())
+	conf.Schedules[0].Expression = conv.SP("rate(1 hour)")
+	assert.Nil(t, conf.Validate())
+	conf.Schedules[0].Expression = conv.SP("rate(1 week)")
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Schedules[0].Count = conv.U16P(0)
+	assert.NotNil(t, conf.Validate())
+	conf.Schedules[0].Count = conv.U16P(11)
+	assert.NotNil(t, conf.Validate())
+
 	// Logging Driver
 	conf = DefaultConfig("app1")
 	conf.Logging.Driver = nil
@@ -305,6 +593,15 @@ func TestConfig_Validate(t *testing.T) {
 	conf.AWS.ELBTargetGroupName = conv.SP("123456789012345678901234567890121") // too long
 	assert.NotNil(t, conf.Validate())
 
+	// AWS ELB Green Target Group Name
+	conf = DefaultConfig("app1")
+	conf.AWS.ELBGreenTargetGroupName = nil
+	assert.NotNil(t, conf.Validate())
+	conf.AWS.ELBGreenTargetGroupName = conv.SP("na-me")
+	assert.Nil(t, conf.Validate())
+	conf.AWS.ELBGreenTargetGroupName = conf.AWS.ELBTargetGroupName // same as blue
+	assert.NotNil(t, conf.Validate())
+
 	// AWS ELB LB Security Gorup Name
 	conf = DefaultConfig("app1")
 	conf.AWS.ELBSecurityGroupName = nil
@@ -340,4 +637,76 @@ func TestConfig_Validate(t *testing.T) {
 	assert.NotNil(t, conf.Validate())
 	conf.Docker.Bin = conv.SP("")
 	assert.NotNil(t, conf.Validate())
+
+	// Docker Image Tag
+	conf = DefaultConfig("app1")
+	conf.Docker.ImageTag = nil
+	assert.NotNil(t, conf.Validate())
+	conf.Docker.ImageTag = conv.SP("")
+	assert.NotNil(t, conf.Validate())
+	conf.Docker.ImageTag = conv.SP("{sha}")
+	assert.Nil(t, conf.Validate())
+	conf.Docker.ImageTag = conv.SP("release-{timestamp}")
+	assert.Nil(t, conf.Validate())
+	conf.Docker.ImageTag = conv.SP("latest")
+	assert.Nil(t, conf.Validate())
+	conf.Docker.ImageTag = conv.SP("v1:{sha}")
+	assert.NotNil(t, conf.Validate())
+
+	// Env secrets
+	conf = DefaultConfig("app1")
+	conf.Env["DB_PASSWORD"] = "ssm:/prod/db/password"
+	conf.Env["API_KEY"] = "secretsmanager:arn:aws:secretsmanager:us-west-2:123456789012:secret:api-key-AbCdEf"
+	assert.Nil(t, conf.Validate())
+	conf.Env["DB_PASSWORD"] = "ssm:"
+	assert.NotNil(t, conf.Validate())
+
+	// Containers
+	conf = testClone(refConfig)
+	assert.Nil(t, conf.Validate())
+	conf.Containers[0].Name = conv.SP("echo") // same as app container
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers = append(conf.Containers, testClone(refConfig).Containers[0]) // duplicate name
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].Image = conv.SP("")
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].Memory = nil
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].MemoryReservation = conv.SP("256m") // > memory
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].CPU = conv.F64P(-1)
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].Ports[0].ContainerPort = conv.U16P(0)
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].Ports[0].Protocol = conv.SP("http")
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].DependsOn[0].Container = conv.SP("unknown")
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].DependsOn[0].Container = conv.SP("proxy") // self
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].DependsOn[0].Condition = conv.SP("READY")
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].DependsOn[0].Condition = conv.SP("HEALTHY")
+	assert.Nil(t, conf.Validate())
+	conf.HealthCheck.Command = nil // app container has no health check
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].HealthCheck.Retries = conv.U16P(11)
+	assert.NotNil(t, conf.Validate())
+	conf = testClone(refConfig)
+	conf.Containers[0].Links = []string{"echo:app"}
+	assert.Nil(t, conf.Validate())
+	conf.Containers[0].Links = []string{"unknown"}
+	assert.NotNil(t, conf.Validate())
 }
@@ -98,3 +98,9 @@ func Error(message string) {
 		ColorFnErrorHeader("Error:"),
 		ColorFnErrorMessage(message))
 }
+
+func Warning(message string) {
+	errorfFn("%s %s\n",
+		ColorFnWarningHeader("Warning:"),
+		ColorFnErrorMessage(message))
+}
@@ -42,8 +42,9 @@ var (
 	ColorFnResource         = cc.Green
 	ColorFnResourceNegative = cc.Red
 
-	ColorFnErrorHeader  = cc.Red
-	ColorFnErrorMessage = regularFn
+	ColorFnErrorHeader   = cc.Red
+	ColorFnWarningHeader = cc.Yellow
+	ColorFnErrorMessage  = regularFn
 
 	//ColorFnShellCommand = concat(cc.Bold, cc.YellowH)
 	ColorFnShellCommand = cc.Cyan
@@ -56,6 +57,7 @@ var (
 	ColorFnMarkProcessing = cc.BlueH
 	ColorFnMarkQuestion   = cc.BlackH
 	ColorFnMarkShell      = regularFn
+	ColorFnMarkNoChange   = cc.BlackH
 )
 
 var (
@@ -65,6 +67,7 @@ var (
 	MarkProcessing = "[*]"
 	MarkQuestion   = ">"
 	MarkShell      = ">"
+	MarkNoChange   = "[=]"
 )
 
 func Blank() {
@@ -161,6 +164,46 @@ func ProcessingOnResource(message, resourceName string, mayTakeLong bool) {
 		sideNote)
 }
 
+func PlanAddResource(message, resourceName string) {
+	printfFn("%s %s%s%s\n",
+		ColorFnMarkAdd(MarkAdd),
+		ColorFnInfoMessage(message+" ["),
+		ColorFnResource(resourceName),
+		ColorFnInfoMessage("]"))
+}
+
+func PlanUpdateResource(message, resourceName string) {
+	printfFn("%s %s%s%s\n",
+		ColorFnMarkUpdate(MarkUpdate),
+		ColorFnInfoMessage(message+" ["),
+		ColorFnResource(resourceName),
+		ColorFnInfoMessage("]"))
+}
+
+func PlanRemoveResource(message, resourceName string) {
+	printfFn("%s %s%s%s\n",
+		ColorFnMarkRemove(MarkRemove),
+		ColorFnInfoMessage(message+" ["),
+		ColorFnResourceNegative(resourceName),
+		ColorFnInfoMessage("]"))
+}
+
+func PlanNoChangeResource(message, resourceName string) {
+	printfFn("%s %s%s%s\n",
+		ColorFnMarkNoChange(MarkNoChange),
+		ColorFnInfoMessage(message+" ["),
+		ColorFnResource(resourceName),
+		ColorFnInfoMessage("]"))
+}
+
+func PlanChange(message, before, after string) {
+	printfFn("  %s %s %s %s\n",
+		ColorFnDetailMessage(message+":"),
+		ColorFnResourceNegative(before),
+		ColorFnDetailMessage("->"),
+		ColorFnResource(after))
+}
+
 func ShellCommand(message string) {
 	printfFn("%s %s\n",
 		ColorFnMarkShell(MarkShell),
@@ -171,6 +214,10 @@ func ShellOutput(message string) {
 	printfFn("%s\n", ColorFnShellOutput(message))
 }
 
+func LogEvent(source, message string) {
+	printfFn("%s %s\n", ColorFnSideNote(source), message)
+}
+
 func ShellError(message string) {
 	printfFn("%s\n", ColorFnShellError(message))
 }
@@ -1,9 +1,11 @@
 package core
 
 import (
-	"path/filepath"
-
+	"crypto/sha1"
 	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/coldbrewcloud/coldbrew-cli/utils"
 )
@@ -16,10 +18,59 @@ func DefaultECSServiceName(appName string) string {
 	return appName
 }
 
+// DefaultECSGreenServiceName returns the name of the second ECS Service used in blue/green deployments.
+func DefaultECSGreenServiceName(appName string) string {
+	return fmt.Sprintf("%s-green", appName)
+}
+
+// DefaultECSServiceScalingPolicyName returns the name of the target tracking scaling policy of the app's
+// ECS Service for the metric. ("cpu", "memory", or "requests")
+func DefaultECSServiceScalingPolicyName(metric string) string {
+	return fmt.Sprintf("%s%s-target", defaultPrefix, metric)
+}
+
 func DefaultECSTaskMainContainerName(appName string) string {
 	return appName
 }
 
+// DefaultECSTaskExecutionRoleName returns the name of the IAM Role that ECS uses to pull secrets
+// for the app's containers. IAM role names cannot exceed 64 characters.
+func DefaultECSTaskExecutionRoleName(clusterName, appName string) string {
+	return iamRoleName(clusterName, appName, "-exec-role")
+}
+
+func DefaultECSTaskExecutionSecretsPolicyName() string {
+	return "secrets"
+}
+
+// DefaultECSTaskRoleName returns the name of the IAM Role that the app's containers run with.
+// IAM role names cannot exceed 64 characters.
+func DefaultECSTaskRoleName(clusterName, appName string) string {
+	return iamRoleName(clusterName, appName, "-task-role")
+}
+
+// DefaultECSTaskRolePolicyName returns the name of the inline policy of the task role that has the
+// policy statements from the app configuration.
+func DefaultECSTaskRolePolicyName() string {
+	return "app"
+}
+
+// iamRoleName returns "coldbrew-{cluster}-{app}{suffix}". If it exceeds the 64 characters limit, the cluster and
+// app part is truncated and followed by a short hash of it instead, so that the suffix that tells the roles of the
+// app apart is always kept.
+func iamRoleName(clusterName, appName, suffix string) string {
+	const maxLen = 64
+
+	base := fmt.Sprintf("%s-%s", clusterName, appName)
+	if len(defaultPrefix)+len(base)+len(suffix) <= maxLen {
+		return defaultPrefix + base + suffix
+	}
+
+	hash := fmt.Sprintf("%x", sha1.Sum([]byte(base)))[:8]
+	base = base[:maxLen-len(defaultPrefix)-len(suffix)-len(hash)-1]
+	return fmt.Sprintf("%s%s-%s%s", defaultPrefix, base, hash, suffix)
+}
+
 func DefaultAppName(appDirectoryOrConfigFile string) string {
 	isDir, err := utils.IsDirectory(appDirectoryOrConfigFile)
 	if err != nil {
@@ -51,6 +102,10 @@ func DefaultELBTargetGroupName(appName string) string {
 	return fmt.Sprintf("%s-elb-tg", appName)
 }
 
+func DefaultELBGreenTargetGroupName(appName string) string {
+	return fmt.Sprintf("%s-green-tg", appName)
+}
+
 func DefaultELBLoadBalancerSecurityGroupName(appName string) string {
 	return fmt.Sprintf("%s-elb-sg", appName)
 }
@@ -62,3 +117,63 @@ func DefaultECRRepository(appName string) string {
 func DefaultCloudWatchLogsGroupName(appName, clusterName string) string {
 	return fmt.Sprintf("coldbrew-%s-%s", clusterName, appName)
 }
+
+// DefaultCloudWatchEventsRuleName returns the name of the CloudWatch Events Rule of the app's schedule. Cluster and
+// app names cannot have dots, so that the rules of an app can be listed by the name prefix.
+func DefaultCloudWatchEventsRuleName(clusterName, appName, scheduleName string) string {
+	return DefaultCloudWatchEventsRuleNamePrefix(clusterName, appName) + scheduleName
+}
+
+func DefaultCloudWatchEventsRuleNamePrefix(clusterName, appName string) string {
+	return fmt.Sprintf("%s%s.%s.", defaultPrefix, clusterName, appName)
+}
+
+// DefaultECSEventsRoleName returns the name of the IAM Role that CloudWatch Events uses to run the app's
+// scheduled tasks. IAM role names cannot exceed 64 characters.
+func DefaultECSEventsRoleName(clusterName, appName string) string {
+	return iamRoleName(clusterName, appName, "-events-role")
+}
+
+// DefaultCloudWatchLogsStreamPrefix returns the awslogs stream prefix of the app's containers. With the prefix,
+// log streams are named "{prefix}/{container name}/{ECS task ID}".
+func DefaultCloudWatchLogsStreamPrefix() string {
+	return "coldbrew"
+}
+
+// DefaultDockerImageTag is the image tag template used when building Docker images.
+// See RenderDockerImageTag for the supported placeholders.
+const DefaultDockerImageTag = "{short_sha}{dirty}"
+
+// RenderDockerImageTag replaces the placeholders in the image tag template:
+//
+//	{sha}        full git commit SHA
+//	{short_sha}  first 7 characters of the git commit SHA
+//	{dirty}      "-dirty.<timestamp>" if the working tree has uncommitted changes, "" otherwise
+//	{timestamp}  UTC build time ("20060102150405")
+func RenderDockerImageTag(template, gitSHA string, dirty bool, now time.Time) string {
+	timestamp := now.UTC().Format("20060102150405")
+
+	shortSHA := gitSHA
+	if len(shortSHA) > 7 {
+		shortSHA = shortSHA[:7]
+	}
+
+	dirtyMarker := ""
+	if dirty {
+		dirtyMarker = "-dirty." + timestamp
+	}
+
+	return strings.NewReplacer(
+		"{sha}", gitSHA,
+		"{short_sha}", shortSHA,
+		"{dirty}", dirtyMarker,
+		"{timestamp}", timestamp,
+	).Replace(template)
+}
+
+// DockerImageTagUsesGit returns true if the image tag template refers to git commit information.
+func DockerImageTagUsesGit(template string) bool {
+	return strings.Contains(template, "{sha}") ||
+		strings.Contains(template, "{short_sha}") ||
+		strings.Contains(template, "{dirty}")
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIAMRoleNames(t *testing.T) {
+	assert.Equal(t, "coldbrew-cluster1-app1-exec-role", DefaultECSTaskExecutionRoleName("cluster1", "app1"))
+	assert.Equal(t, "coldbrew-cluster1-app1-task-role", DefaultECSTaskRoleName("cluster1", "app1"))
+	assert.Equal(t, "coldbrew-cluster1-app1-events-role", DefaultECSEventsRoleName("cluster1", "app1"))
+
+	// max length cluster and app names
+	clusterName := strings.Repeat("c", 32)
+	appName := strings.Repeat("a", 32)
+	execRoleName := DefaultECSTaskExecutionRoleName(clusterName, appName)
+	taskRoleName := DefaultECSTaskRoleName(clusterName, appName)
+	eventsRoleName := DefaultECSEventsRoleName(clusterName, appName)
+	assert.Len(t, execRoleName, 64)
+	assert.Len(t, taskRoleName, 64)
+	assert.Len(t, eventsRoleName, 64)
+	assert.True(t, strings.HasSuffix(execRoleName, "-exec-role"))
+	assert.True(t, strings.HasSuffix(taskRoleName, "-task-role"))
+	assert.True(t, strings.HasSuffix(eventsRoleName, "-events-role"))
+
+	// names that share the same truncated prefix should not collide
+	assert.NotEqual(t,
+		DefaultECSTaskRoleName(clusterName, appName),
+		DefaultECSTaskRoleName(clusterName, appName[:31]+"b"))
+}
@@ -8,6 +8,10 @@ import (
 const (
 	AWSTagNameResourceName     = "Name"
 	AWSTagNameCreatedTimestamp = "coldbrew_cli_created"
+
+	// ELBBlueGreenHeaderName is the HTTP header of the temporary listener rule that associates the ELB Target
+	// Group of the idle blue/green slot with the load balancer during a deployment. (value: target group name)
+	ELBBlueGreenHeaderName = "X-Coldbrew-Target-Group"
 )
 
 func DefaultTagsForAWSResources(resourceName string) map[string]string {
@@ -5,11 +5,15 @@ import "fmt"
 const defaultPrefix = "coldbrew-"
 
 const (
-	EC2AssumeRolePolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action": "sts:AssumeRole"}]}`
-	ECSAssumeRolePolicy = `{"Version":"2008-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ecs.amazonaws.com"},"Action": "sts:AssumeRole"}]}`
-
-	AdministratorAccessPolicyARN = "arn:aws:iam::aws:policy/AdministratorAccess"
-	ECSServiceRolePolicyARN      = "arn:aws:iam::aws:policy/service-role/AmazonEC2ContainerServiceRole"
+	EC2AssumeRolePolicy      = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action": "sts:AssumeRole"}]}`
+	ECSAssumeRolePolicy      = `{"Version":"2008-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ecs.amazonaws.com"},"Action": "sts:AssumeRole"}]}`
+	ECSTasksAssumeRolePolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ecs-tasks.amazonaws.com"},"Action": "sts:AssumeRole"}]}`
+	EventsAssumeRolePolicy   = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"events.amazonaws.com"},"Action": "sts:AssumeRole"}]}`
+
+	ECSServiceRolePolicyARN           = "arn:aws:iam::aws:policy/service-role/AmazonEC2ContainerServiceRole"
+	ECSContainerInstanceRolePolicyARN = "arn:aws:iam::aws:policy/service-role/AmazonEC2ContainerServiceforEC2Role"
+	ECSTaskExecutionRolePolicyARN     = "arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy"
+	ECSEventsRolePolicyARN            = "arn:aws:iam::aws:policy/service-role/AmazonEC2ContainerServiceEventsRole"
 )
 
 func DefaultECSClusterName(clusterName string) string {
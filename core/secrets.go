@@ -0,0 +1,45 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	EnvSecretSourceSSM            = "ssm"
+	EnvSecretSourceSecretsManager = "secretsmanager"
+)
+
+// ParseEnvSecret returns the secret source and reference if the environment variable value refers to
+// a secret (e.g. "ssm:/prod/db/password" or "secretsmanager:arn:aws:secretsmanager:...").
+func ParseEnvSecret(value string) (source, reference string, ok bool) {
+	for _, s := range []string{EnvSecretSourceSSM, EnvSecretSourceSecretsManager} {
+		if strings.HasPrefix(value, s+":") {
+			return s, strings.TrimPrefix(value, s+":"), true
+		}
+	}
+	return "", "", false
+}
+
+// SecretResourceARN returns the ARN (with wildcards where unknown) of the secret for IAM policies.
+func SecretResourceARN(source, reference, region string) string {
+	switch source {
+	case EnvSecretSourceSSM:
+		if strings.HasPrefix(reference, "arn:") {
+			return reference
+		}
+		return fmt.Sprintf("arn:aws:ssm:%s:*:parameter/%s", region, strings.TrimPrefix(reference, "/"))
+	case EnvSecretSourceSecretsManager:
+		if strings.HasPrefix(reference, "arn:") {
+			// strip JSON key, version stage and version ID (":key:stage:id") if present
+			tokens := strings.Split(reference, ":")
+			if len(tokens) > 7 {
+				tokens = tokens[:7]
+			}
+			return strings.Join(tokens, ":")
+		}
+		// Secrets Manager appends 6 random characters to the secret name
+		return fmt.Sprintf("arn:aws:secretsmanager:%s:*:secret:%s-??????", region, reference)
+	}
+	return ""
+}
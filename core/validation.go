@@ -11,18 +11,41 @@ const (
 	MaxAppUnits      = uint16(1000)
 	MaxAppCPU        = float64(1024 * 16)
 	MaxAppMemoryInMB = uint64(1024 * 16)
+
+	MaxPlacementStrategies  = 5
+	MaxPlacementConstraints = 10
+
+	MaxIAMManagedPolicies = 10 // per IAM role
+
+	MaxContainerStopTimeoutInSeconds = 120
+
+	MaxScheduledTaskCount             = uint16(10) // per ECS RunTask
+	MaxCloudWatchEventsRuleNameLength = 64
 )
 
 var (
-	AppNameRE              = regexp.MustCompile(`^[\w\-]{1,32}$`)
-	ClusterNameRE          = regexp.MustCompile(`^[\w\-]{1,32}$`)
-	ELBNameRE              = regexp.MustCompile(`^(?:[a-zA-Z0-9][a-zA-Z0-9\-]{0,30})?[a-zA-Z0-9]$`)
-	ELBTargetGroupNameRE   = regexp.MustCompile(`^(?:[a-zA-Z0-9][a-zA-Z0-9\-]{0,30})?[a-zA-Z0-9]$`)
-	ELBSecurityGroupNameRE = regexp.MustCompile(`^(?:[a-zA-Z0-9][a-zA-Z0-9\-]{0,30})?[a-zA-Z0-9]$`)
-	ECRRepoNameRE          = regexp.MustCompile(`^.{1,256}$`)                       // TODO: need better matcher
-	HealthCheckPathRE      = regexp.MustCompile(`^.+$`)                             // TODO: need better matcher
-	HealthCheckStatusRE    = regexp.MustCompile(`^\d{3}-\d{3}$|^\d{3}(?:,\d{3})*$`) // "200", "200-299", "200,204,201"
-	DockerImageURIRE       = regexp.MustCompile(`^([^:]+)(?::([^:]+))?$`)
+	AppNameRE                = regexp.MustCompile(`^[\w\-]{1,32}$`)
+	ClusterNameRE            = regexp.MustCompile(`^[\w\-]{1,32}$`)
+	ELBNameRE                = regexp.MustCompile(`^(?:[a-zA-Z0-9][a-zA-Z0-9\-]{0,30})?[a-zA-Z0-9]$`)
+	ELBTargetGroupNameRE     = regexp.MustCompile(`^(?:[a-zA-Z0-9][a-zA-Z0-9\-]{0,30})?[a-zA-Z0-9]$`)
+	ELBSecurityGroupNameRE   = regexp.MustCompile(`^(?:[a-zA-Z0-9][a-zA-Z0-9\-]{0,30})?[a-zA-Z0-9]$`)
+	ECRRepoNameRE            = regexp.MustCompile(`^.{1,256}$`)                       // TODO: need better matcher
+	HealthCheckPathRE        = regexp.MustCompile(`^.+$`)                             // TODO: need better matcher
+	HealthCheckStatusRE      = regexp.MustCompile(`^\d{3}-\d{3}$|^\d{3}(?:,\d{3})*$`) // "200", "200-299", "200,204,201"
+	DockerImageURIRE         = regexp.MustCompile(`^([^:]+)(?::([^:]+))?$`)
+	ContainerNameRE          = regexp.MustCompile(`^[\w\-]{1,255}$`)
+	DockerImageTagRE         = regexp.MustCompile(`^[\w][\w.\-]{0,127}$`)
+	DockerImageTagTemplateRE = regexp.MustCompile(`^[\w{][\w.\-{}]{0,127}$`)
+	VolumeNameRE             = regexp.MustCompile(`^[\w\-]{1,255}$`)
+	EFSFileSystemIDRE        = regexp.MustCompile(`^fs-[0-9a-f]{8,40}$`)
+	EFSAccessPointIDRE       = regexp.MustCompile(`^fsap-[0-9a-f]{8,40}$`)
+	IAMPolicyARNRE           = regexp.MustCompile(`^arn:aws[\w\-]*:iam::(?:aws|\d{12}):policy/.+$`)
+	ScheduleNameRE           = regexp.MustCompile(`^[\w\-]{1,64}$`)
+	ScheduleExpressionRE     = regexp.MustCompile(`^cron\([^()]+\)$|^rate\(\d+ (?:minute|minutes|hour|hours|day|days)\)$`)
+
+	// resource limits that Docker supports for ulimits
+	UlimitNames = []string{"core", "cpu", "data", "fsize", "locks", "memlock", "msgqueue", "nice", "nofile",
+		"nproc", "rss", "rtprio", "rttime", "sigpending", "stack"}
 
 	SizeExpressionRE = regexp.MustCompile(`^(\d+)(?:([kmgtKMGT])([bB])?)?$`)
 	TimeExpressionRE = regexp.MustCompile(`^(\d+)([smhSMH])?$`)
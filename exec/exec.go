@@ -3,6 +3,7 @@ package exec
 import (
 	"bufio"
 	"errors"
+	"os"
 	"os/exec"
 )
 
@@ -63,3 +64,27 @@ func Exec(name string, args ...string) (stdout chan string, stderr chan string,
 
 	return
 }
+
+// Output runs the command and returns its standard output once it exits.
+func Output(name string, args ...string) (string, error) {
+	if name == "" {
+		return "", errors.New("name is empty")
+	}
+
+	out, err := exec.Command(name, args...).Output()
+	return string(out), err
+}
+
+// Interactive runs the command with the standard input, output and error of this process, and returns once it
+// exits. Returned error is *exec.ExitError if the command exits with non-zero status.
+func Interactive(name string, args ...string) error {
+	if name == "" {
+		return errors.New("name is empty")
+	}
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
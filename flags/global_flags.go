@@ -20,6 +20,7 @@ type GlobalFlags struct {
 	AppDirectory     *string `json:"app-dir,omitempty"`
 	ConfigFile       *string `json:"config,omitempty"`
 	ConfigFileFormat *string `json:"config-format,omitempty"`
+	Environment      *string `json:"environment,omitempty"`
 	DisableColoring  *bool   `json:"disable-color,omitempty"`
 	Verbose          *bool   `json:"verbose,omitempty"`
 	AWSAccessKey     *string `json:"aws-access-key,omitempty"`
@@ -33,6 +34,7 @@ func NewGlobalFlags(ka *kingpin.Application) *GlobalFlags {
 		AppDirectory:     ka.Flag("app-dir", "Application directory").Short('D').Default(".").String(),
 		ConfigFile:       ka.Flag("config", "Configuration file path").Short('C').Default("").String(),
 		ConfigFileFormat: ka.Flag("config-format", "Configuraiton file format (JSON/YAML)").Default(GlobalFlagsConfigFileFormatYAML).String(),
+		Environment:      ka.Flag("environment", "Environment defined in the configuration file ($COLDBREW_ENV)").Envar("COLDBREW_ENV").Default("").String(),
 		DisableColoring:  ka.Flag("disable-color", "Disable colored outputs").Bool(),
 		Verbose:          ka.Flag("verbose", "Enable verbose logging").Short('V').Default("false").Bool(),
 		AWSAccessKey:     ka.Flag("aws-access-key", "AWS Access Key ID ($AWS_ACCESS_KEY_ID)").Envar("AWS_ACCESS_KEY_ID").Default("").String(),
@@ -13,6 +13,7 @@ func TestNewGlobalFlags(t *testing.T) {
 
 	testStringFlag(t, app, &gf.ConfigFile, nil, "config", testBytePtr('C'), nil, nil)
 	testStringFlag(t, app, &gf.ConfigFileFormat, nil, "config-format", nil, testSptr(GlobalFlagsConfigFileFormatYAML), nil)
+	testStringFlag(t, app, &gf.Environment, nil, "environment", nil, nil, testSptr("COLDBREW_ENV"))
 	testStringFlag(t, app, &gf.AppDirectory, nil, "app-dir", testBytePtr('D'), testSptr("."), nil)
 	testBoolFlag(t, app, &gf.Verbose, nil, "verbose", testBytePtr('V'), testBptr(false))
 	testStringFlag(t, app, &gf.AWSAccessKey, nil, "aws-access-key", nil, nil, testSptr("AWS_ACCESS_KEY_ID"))
@@ -0,0 +1,41 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coldbrewcloud/coldbrew-cli/exec"
+)
+
+type Client struct {
+	gitBin string
+}
+
+func NewClient(gitBin string) *Client {
+	return &Client{
+		gitBin: gitBin,
+	}
+}
+
+func (c *Client) GitBinAvailable() bool {
+	_, err := exec.Output(c.gitBin, "version")
+	return err == nil
+}
+
+// CommitSHA returns the full SHA of the commit checked out in the working tree that contains path.
+func (c *Client) CommitSHA(path string) (string, error) {
+	out, err := exec.Output(c.gitBin, "-C", path, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("Failed to determine git commit [%s]: %s", path, err.Error())
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// IsDirty returns true if the working tree that contains path has uncommitted changes.
+func (c *Client) IsDirty(path string) (bool, error) {
+	out, err := exec.Output(c.gitBin, "-C", path, "status", "--porcelain")
+	if err != nil {
+		return false, fmt.Errorf("Failed to determine git status [%s]: %s", path, err.Error())
+	}
+	return strings.TrimSpace(out) != "", nil
+}
@@ -12,6 +12,12 @@ import (
 	"github.com/coldbrewcloud/coldbrew-cli/commands/create"
 	"github.com/coldbrewcloud/coldbrew-cli/commands/delete"
 	"github.com/coldbrewcloud/coldbrew-cli/commands/deploy"
+	"github.com/coldbrewcloud/coldbrew-cli/commands/events"
+	"github.com/coldbrewcloud/coldbrew-cli/commands/exec"
+	"github.com/coldbrewcloud/coldbrew-cli/commands/history"
+	"github.com/coldbrewcloud/coldbrew-cli/commands/logs"
+	"github.com/coldbrewcloud/coldbrew-cli/commands/rollback"
+	"github.com/coldbrewcloud/coldbrew-cli/commands/run"
 	"github.com/coldbrewcloud/coldbrew-cli/commands/status"
 	"github.com/coldbrewcloud/coldbrew-cli/console"
 	"github.com/coldbrewcloud/coldbrew-cli/flags"
@@ -71,7 +77,13 @@ func registerCommands(ka *kingpin.Application, globalFlags *flags.GlobalFlags) m
 	cmds := []commands.Command{
 		&create.Command{},
 		&deploy.Command{},
+		&rollback.Command{},
+		&run.Command{},
 		&status.Command{},
+		&logs.Command{},
+		&events.Command{},
+		&history.Command{},
+		&exec.Command{},
 		&delete.Command{},
 		&clustercreate.Command{},
 		&clusterstatus.Command{},